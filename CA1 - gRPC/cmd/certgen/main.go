@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"dist-grpc/pkg/tlsutil"
+)
+
+func init() {
+	log.SetPrefix("Cert Gen")
+	log.SetTimeFormat(time.TimeOnly)
+}
+
+func main() {
+	outPtr := flag.String("out", "certs", "directory to write the certificates to")
+	hostsPtr := flag.String("hosts", "localhost,127.0.0.1,::1", "comma-separated hosts of the server certificate")
+	validityPtr := flag.Duration("validity", 365*24*time.Hour, "validity of the generated certificates")
+	flag.Parse()
+
+	out := *outPtr
+	hosts := strings.Split(*hostsPtr, ",")
+	if err := os.MkdirAll(out, 0o755); err != nil {
+		log.Fatalf("Failed to create output directory: %v", err)
+	}
+
+	ca, err := tlsutil.GenerateCA("dist-grpc dev CA", *validityPtr)
+	if err != nil {
+		log.Fatalf("Failed to generate CA: %v", err)
+	}
+	server, err := tlsutil.GenerateLeaf(ca, "dist-grpc server", hosts, true, *validityPtr)
+	if err != nil {
+		log.Fatalf("Failed to generate server certificate: %v", err)
+	}
+	client, err := tlsutil.GenerateLeaf(ca, "dist-grpc client", nil, false, *validityPtr)
+	if err != nil {
+		log.Fatalf("Failed to generate client certificate: %v", err)
+	}
+
+	pairs := []struct {
+		name string
+		kp   *tlsutil.KeyPair
+	}{
+		{"ca", ca},
+		{"server", server},
+		{"client", client},
+	}
+	for _, p := range pairs {
+		certFile := filepath.Join(out, p.name+".pem")
+		keyFile := filepath.Join(out, p.name+"-key.pem")
+		if err := p.kp.WriteFiles(certFile, keyFile); err != nil {
+			log.Fatalf("Failed to write %s certificate: %v", p.name, err)
+		}
+		log.Info("Wrote certificate", "cert", certFile, "key", keyFile)
+	}
+}
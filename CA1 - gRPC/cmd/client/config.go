@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/log"
+	"google.golang.org/grpc/status"
+
+	pb "dist-grpc/pkg/proto"
+)
+
+func reportError(err error) {
+	log.Error("RPC failed", "code", status.Code(err), "err", err)
+}
+
+type methodName struct {
+	Service string `json:"service"`
+	Method  string `json:"method,omitempty"`
+}
+
+type retryPolicy struct {
+	MaxAttempts          int      `json:"maxAttempts"`
+	InitialBackoff       string   `json:"initialBackoff"`
+	MaxBackoff           string   `json:"maxBackoff"`
+	BackoffMultiplier    float64  `json:"backoffMultiplier"`
+	RetryableStatusCodes []string `json:"retryableStatusCodes"`
+}
+
+type methodConfig struct {
+	Name        []methodName `json:"name"`
+	RetryPolicy *retryPolicy `json:"retryPolicy,omitempty"`
+}
+
+type serviceConfig struct {
+	LoadBalancingConfig []map[string]struct{} `json:"loadBalancingConfig,omitempty"`
+	MethodConfig        []methodConfig        `json:"methodConfig"`
+}
+
+var balancingPolicies = []string{"round_robin", "pick_first"}
+
+// defaultServiceConfig retries the idempotent calls, that is the unary and
+// server streaming searches and the lookup by id, while the server is
+// unavailable. Streams are only retried before their first response. RPCs
+// are spread over the resolved servers with the balancing policy.
+func defaultServiceConfig(maxAttempts int, policy string) (string, error) {
+	service := pb.OrderManagement_ServiceDesc.ServiceName
+	config := serviceConfig{LoadBalancingConfig: []map[string]struct{}{{policy: {}}}}
+	if maxAttempts > 1 {
+		config.MethodConfig = append(config.MethodConfig, methodConfig{
+			Name: []methodName{
+				{Service: service, Method: "GetOrderUnary"},
+				{Service: service, Method: "GetOrderServerStream"},
+				{Service: service, Method: "GetOrderByID"},
+			},
+			RetryPolicy: &retryPolicy{
+				MaxAttempts:          maxAttempts,
+				InitialBackoff:       "0.1s",
+				MaxBackoff:           "1s",
+				BackoffMultiplier:    2,
+				RetryableStatusCodes: []string{"UNAVAILABLE"},
+			},
+		})
+	}
+	data, err := json.Marshal(config)
+	return string(data), err
+}
+
+// serviceConfigFlags are the flags the default service config is built from.
+// A service config file replaces them, so they cannot be given along with one.
+var serviceConfigFlags = []string{"max-attempts", "lb"}
+
+// loadServiceConfig reads the service config file at path, or builds the
+// default one when there is none. set holds the flags given on the command
+// line.
+func loadServiceConfig(path string, maxAttempts int, policy string, set map[string]bool) (string, error) {
+	if path == "" {
+		return defaultServiceConfig(maxAttempts, policy)
+	}
+	for _, name := range serviceConfigFlags {
+		if set[name] {
+			return "", fmt.Errorf("-%s cannot be combined with -service-config, set it in the service config instead", name)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	if !json.Valid(data) {
+		return "", fmt.Errorf("%s is not valid JSON", path)
+	}
+	return string(data), nil
+}
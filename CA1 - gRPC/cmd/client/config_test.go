@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"dist-grpc/pkg/harness"
+	"dist-grpc/pkg/interceptor"
+	"dist-grpc/pkg/matcher"
+	"dist-grpc/pkg/orderclient"
+	"dist-grpc/pkg/server"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "service-config.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadServiceConfig(t *testing.T) {
+	file := `{"loadBalancingConfig": [{"pick_first": {}}]}`
+	path := writeConfig(t, file)
+	tests := []struct {
+		name        string
+		path        string
+		maxAttempts int
+		policy      string
+		set         map[string]bool
+		wantPolicy  string
+		wantRetries int
+		wantErr     bool
+	}{
+		{"default", "", 4, "round_robin", nil, "round_robin", 4, false},
+		{"flags", "", 2, "pick_first", map[string]bool{"lb": true, "max-attempts": true}, "pick_first", 2, false},
+		{"no retries", "", 1, "round_robin", map[string]bool{"max-attempts": true}, "round_robin", 0, false},
+		{"file", path, 4, "round_robin", map[string]bool{"service-config": true}, "pick_first", 0, false},
+		{"file with lb", path, 4, "pick_first", map[string]bool{"service-config": true, "lb": true}, "", 0, true},
+		{"file with max attempts", path, 2, "round_robin", map[string]bool{"service-config": true, "max-attempts": true}, "", 0, true},
+		{"missing file", filepath.Join(t.TempDir(), "missing.json"), 4, "round_robin", nil, "", 0, true},
+		{"invalid file", writeConfig(t, `{"loadBalancingConfig": [`), 4, "round_robin", nil, "", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := loadServiceConfig(tt.path, tt.maxAttempts, tt.policy, tt.set)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("got %s, want an error", data)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var config serviceConfig
+			if err := json.Unmarshal([]byte(data), &config); err != nil {
+				t.Fatal(err)
+			}
+			if len(config.LoadBalancingConfig) != 1 {
+				t.Fatalf("got balancing config %v, want %s", config.LoadBalancingConfig, tt.wantPolicy)
+			}
+			if _, ok := config.LoadBalancingConfig[0][tt.wantPolicy]; !ok {
+				t.Errorf("got balancing config %v, want %s", config.LoadBalancingConfig, tt.wantPolicy)
+			}
+			retries := 0
+			for _, method := range config.MethodConfig {
+				if method.RetryPolicy != nil {
+					retries = method.RetryPolicy.MaxAttempts
+				}
+			}
+			if retries != tt.wantRetries {
+				t.Errorf("got %d attempts, want %d", retries, tt.wantRetries)
+			}
+		})
+	}
+}
+
+// dialReplicas serves three replicas, stamping their instance ids, and dials
+// them with the default service config of the flags. The first failures
+// calls of every replica fail with Unavailable.
+func dialReplicas(t *testing.T, maxAttempts int, policy string, failures int64) *orderclient.Client {
+	t.Helper()
+	replicas := make(map[string]*harness.Harness)
+	var addrs []string
+	for i := 0; i < 3; i++ {
+		id := fmt.Sprintf("replica-%d", i)
+		var calls atomic.Int64
+		failing := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			if calls.Add(1) <= failures {
+				return nil, status.Error(codes.Unavailable, "starting up")
+			}
+			return handler(ctx, req)
+		}
+		store := harness.NewFakeStore(harness.Fruits...)
+		h, err := harness.Start(store, matcher.New(store), server.Config{},
+			grpc.ChainUnaryInterceptor(interceptor.UnaryInstance(id), failing))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(h.Close)
+		replicas[id] = h
+		addrs = append(addrs, id)
+	}
+
+	config, err := loadServiceConfig("", maxAttempts, policy, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target, resolve := orderclient.Resolve(addrs)
+	conn, err := grpc.Dial(target, resolve,
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return replicas[addr].Connect(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultServiceConfig(config),
+		grpc.WithBlock(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return orderclient.New(conn, orderclient.Options{CallTimeout: harness.HandledTimeout})
+}
+
+// servedBy counts the calls each replica served, out of calls unary calls.
+func servedBy(t *testing.T, client *orderclient.Client, calls int) map[string]int {
+	t.Helper()
+	served := make(map[string]int)
+	for i := 0; i < calls; i++ {
+		pages, err := client.Unary(context.Background(), "apple")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, page := range pages {
+			served[page.Instance]++
+		}
+	}
+	return served
+}
+
+func TestBalancingPolicies(t *testing.T) {
+	if served := servedBy(t, dialReplicas(t, 1, "round_robin", 0), 12); len(served) != 3 {
+		t.Errorf("round_robin calls served by %v, want every replica", served)
+	}
+	if served := servedBy(t, dialReplicas(t, 1, "pick_first", 0), 12); len(served) != 1 || served["replica-0"] != 12 {
+		t.Errorf("pick_first calls served by %v, want replica-0 only", served)
+	}
+}
+
+func TestRetries(t *testing.T) {
+	// Every replica fails its first call, so the first call of a pick_first
+	// client needs two attempts and fails without retries.
+	if served := servedBy(t, dialReplicas(t, 2, "pick_first", 1), 2); served["replica-0"] != 2 {
+		t.Errorf("calls served by %v, want replica-0 to serve both after a retry", served)
+	}
+	client := dialReplicas(t, 1, "pick_first", 1)
+	if _, err := client.Unary(context.Background(), "apple"); status.Code(err) != codes.Unavailable {
+		t.Errorf("got %v without retries, want Unavailable", err)
+	}
+}
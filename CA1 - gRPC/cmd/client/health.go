@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// runHealth queries the standard health service and returns the exit code:
+// 0 when the service is serving, 1 otherwise.
+func runHealth(conn *grpc.ClientConn, args []string) int {
+	fs := flag.NewFlagSet("health", flag.ExitOnError)
+	servicePtr := fs.String("service", "", "service to check (default: the whole server)")
+	timeoutPtr := fs.Duration("timeout", 5*time.Second, "deadline of the health check")
+	_ = fs.Parse(args)
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeoutPtr)
+	defer cancel()
+
+	res, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: *servicePtr})
+	if err != nil {
+		log.Errorf("Health check failed: %v", err)
+		return 1
+	}
+	fmt.Println(res.GetStatus())
+	if res.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return 1
+	}
+	return 0
+}
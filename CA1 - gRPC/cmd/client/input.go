@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+const queryHistory = "query"
+
+// lineReader reads whole lines from stdin. On a terminal it offers line
+// editing and a separate history per kind of input; otherwise it falls back
+// to plain buffered reads so that piped input keeps working.
+type lineReader struct {
+	fd        int
+	terminals map[string]*term.Terminal
+	scanner   *bufio.Scanner
+}
+
+func newLineReader() *lineReader {
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		return &lineReader{fd: fd, terminals: make(map[string]*term.Terminal)}
+	}
+	return &lineReader{fd: -1, scanner: bufio.NewScanner(os.Stdin)}
+}
+
+// readLine prints the prompt and returns the next line without its trailing
+// newline. io.EOF is returned on end of input or Ctrl-C / Ctrl-D.
+func (r *lineReader) readLine(prompt string) (string, error) {
+	return r.readLineWithHistory("", prompt)
+}
+
+func (r *lineReader) readLineWithHistory(history, prompt string) (string, error) {
+	if r.terminals == nil {
+		fmt.Print(prompt)
+		if !r.scanner.Scan() {
+			if err := r.scanner.Err(); err != nil {
+				return "", err
+			}
+			return "", io.EOF
+		}
+		return r.scanner.Text(), nil
+	}
+	// Raw mode is only held while a line is edited so that log output
+	// printed in between keeps its normal line endings.
+	state, err := term.MakeRaw(r.fd)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = term.Restore(r.fd, state)
+	}()
+	terminal, ok := r.terminals[history]
+	if !ok {
+		rw := struct {
+			io.Reader
+			io.Writer
+		}{os.Stdin, os.Stdout}
+		terminal = term.NewTerminal(rw, "")
+		r.terminals[history] = terminal
+	}
+	terminal.SetPrompt(prompt)
+	line, err := terminal.ReadLine()
+	if errors.Is(err, term.ErrPasteIndicator) {
+		err = nil
+	}
+	return line, err
+}
+
+func (r *lineReader) readInt(prompt string) (int, error) {
+	line, err := r.readLine(prompt)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(line))
+}
+
+func (r *lineReader) readFloat(prompt string) (float64, error) {
+	line, err := r.readLine(prompt)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(strings.TrimSpace(line), 64)
+}
+
+// parseQuery turns an input line into a query. The whole line is the query,
+// so "red apple" stays one query; a line wrapped in double or single quotes is
+// taken literally, which allows e.g. searching for "exit". The second result
+// reports whether the line was quoted.
+func parseQuery(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if len(line) >= 2 {
+		if line[0] == '"' && line[len(line)-1] == '"' {
+			if unquoted, err := strconv.Unquote(line); err == nil {
+				return unquoted, true
+			}
+		}
+		if line[0] == '\'' && line[len(line)-1] == '\'' {
+			return line[1 : len(line)-1], true
+		}
+	}
+	return line, false
+}
@@ -6,13 +6,23 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/log"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 
+	"dist-grpc/pkg/auth"
+	"dist-grpc/pkg/orderclient"
 	pb "dist-grpc/pkg/proto"
+	"dist-grpc/pkg/tlsutil"
 )
 
 func init() {
@@ -25,187 +35,217 @@ const (
 	defaultHost = "localhost"
 )
 
-func printResponse(res *pb.Response) {
-	fmt.Println("Response:")
-	fmt.Println("\tTimestamp:", res.Timestamp.AsTime())
-	fmt.Println("\tResults:", utils.ToString(res.Results))
+func parseMatchMode(name string) (pb.MatchMode, error) {
+	value, ok := pb.MatchMode_value["MATCH_MODE_"+strings.ToUpper(strings.ReplaceAll(name, "-", "_"))]
+	if !ok {
+		return 0, fmt.Errorf("unknown match mode: %s", name)
+	}
+	return pb.MatchMode(value), nil
 }
 
-func getOrderUnary(client pb.OrderManagementClient, query string) {
-	log.Infof("Sending query as unary RPC: %s", query)
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	req := pb.Request{Query: query}
-	res, err := client.GetOrderUnary(ctx, &req)
-	if err != nil {
-		log.Fatalf("Failed to get order: %v", err)
+func parseConsistency(name string) (pb.ReadConsistency, error) {
+	value, ok := pb.ReadConsistency_value["READ_CONSISTENCY_"+strings.ToUpper(name)]
+	if !ok {
+		return 0, fmt.Errorf("unknown read consistency: %s", name)
 	}
-	log.Info("Received response")
-	printResponse(res)
+	return pb.ReadConsistency(value), nil
 }
 
-func getOrderServerStream(client pb.OrderManagementClient, query string) {
-	log.Infof("Sending query as server stream RPC: %s", query)
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	req := pb.Request{Query: query}
-	stream, err := client.GetOrderServerStream(ctx, &req)
-	if err != nil {
-		log.Fatalf("Failed to get order: %v", err)
-		return
+func parseAggregation(name string) (pb.Aggregation, error) {
+	value, ok := pb.Aggregation_value["AGGREGATION_"+strings.ToUpper(name)]
+	if !ok {
+		return 0, fmt.Errorf("unknown aggregation: %s", name)
 	}
+	return pb.Aggregation(value), nil
+}
 
-	for {
-		resp, err := stream.Recv()
-		if err == io.EOF {
-			log.Warn("Received EOF, closing stream")
-			break
+func printResponse(res orderclient.Response) {
+	fmt.Println("Response:")
+	if res.RequestId != "" {
+		fmt.Println("\tRequest ID:", res.RequestId)
+	}
+	if res.Instance != "" {
+		fmt.Println("\tServed by:", res.Instance)
+	}
+	fmt.Println("\tTimestamp:", res.Timestamp.AsTime())
+	st := responseStatus(res.Response)
+	for _, group := range res.Groups {
+		fmt.Printf("\tQuery %d (%s): %s\n", group.Index, group.Query, utils.ToString(group.Results))
+		if group.Status != nil {
+			fmt.Println("\t\tError:", group.Status.Message)
 		}
-		if err != nil {
-			log.Fatalf("Failed to receive response: %v", err)
+	}
+	for _, count := range res.Counts {
+		fmt.Printf("\t%s: matched by %d queries %s\n", count.Result, count.Count, utils.ToString(count.Queries))
+	}
+	if len(res.Groups) > 0 {
+		fmt.Println("\tTotal:", res.TotalCount)
+	} else if st == nil || len(res.Results) > 0 {
+		fmt.Println("\tResults:", utils.ToString(res.Results))
+		if len(res.Scores) > 0 {
+			fmt.Println("\tScores:", utils.ToString(res.Scores))
+		}
+		fmt.Println("\tTotal:", res.TotalCount)
+	}
+	if res.Partial {
+		fmt.Println("\tPartial: no answer from shards", utils.ToString(res.MissingShards))
+	}
+	if st != nil {
+		fmt.Printf("\tError: %s: %s\n", st.Code(), st.Message())
+		for _, v := range fieldViolations(st) {
+			fmt.Printf("\t\t%s: %s\n", v.GetField(), v.GetDescription())
 		}
-		log.Info("Received single response")
-		printResponse(resp)
 	}
 }
 
-func getOrderClientStream(client pb.OrderManagementClient, queryChan chan string) {
-	log.Info("Sending queries as client stream RPC")
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	stream, err := client.GetOrderClientStream(ctx)
-	if err != nil {
-		log.Fatalf("Failed to get order: %v", err)
-		return
+// responseStatus returns the error a stream reported for some of its
+// queries, or nil when they all succeeded.
+func responseStatus(res *pb.Response) *status.Status {
+	if res.GetStatus() == nil || codes.Code(res.GetStatus().GetCode()) == codes.OK {
+		return nil
 	}
+	return status.FromProto(res.GetStatus())
+}
 
-	for query := range queryChan {
-		log.Infof("Sending query: %s", query)
-		if err := stream.Send(&pb.Request{Query: query}); err != nil {
-			log.Fatalf("Failed to send request: %v", err)
+func fieldViolations(st *status.Status) []*errdetails.BadRequest_FieldViolation {
+	var violations []*errdetails.BadRequest_FieldViolation
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			violations = append(violations, badRequest.GetFieldViolations()...)
 		}
 	}
-
-	log.Info("Closing stream and receiving response")
-	resp, err := stream.CloseAndRecv()
-	if err != nil {
-		log.Fatalf("Failed to receive response: %v", err)
-	}
-	log.Info("Received response")
-	printResponse(resp)
+	return violations
 }
 
-func GetOrderBiDiStream(client pb.OrderManagementClient, queryChan chan string) {
-	log.Info("Sending queries as bidirectional stream RPC")
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+var input *lineReader
 
-	stream, err := client.GetOrderBiDiStream(ctx)
+func readQuery() (string, bool) {
+	line, err := input.readLineWithHistory(queryHistory, "> Enter query: ")
 	if err != nil {
-		log.Fatalf("Failed to get order: %v", err)
-		return
+		log.Error("Failed to read query")
+		return "", false
 	}
+	query, _ := parseQuery(line)
+	return query, true
+}
 
-	waitc := make(chan struct{})
-	go func() {
-		for {
-			resp, err := stream.Recv()
-			if err == io.EOF {
-				log.Warn("Received EOF, closing stream")
-				close(waitc)
-				return
-			}
-			if err != nil {
-				log.Fatalf("Failed to receive response: %v", err)
-			}
-			log.Info("Received single response")
-			printResponse(resp)
+// promptQueries reads queries until 'exit' or the end of input and hands
+// each of them to send, which reports whether the stream still accepts more.
+func promptQueries(send func(query string) bool) {
+	for {
+		line, err := input.readLineWithHistory(queryHistory, "> Enter query (enter 'exit' to finish): ")
+		if err == io.EOF {
+			return
 		}
-	}()
-
-	for query := range queryChan {
-		log.Infof("Sending query: %s", query)
-		if err := stream.Send(&pb.Request{Query: query}); err != nil {
-			log.Fatalf("Failed to send request: %v", err)
+		if err != nil {
+			log.Errorf("Failed to read query: %v", err)
+			return
+		}
+		query, quoted := parseQuery(line)
+		if !quoted && query == "exit" {
+			return
+		}
+		if query == "" {
+			continue
+		}
+		if !send(query) {
+			return
 		}
 	}
-	err = stream.CloseSend()
-	if err != nil {
-		log.Fatalf("Failed to close send: %v", err)
-	}
-	<-waitc
 }
 
-func unaryRPC(client pb.OrderManagementClient) {
-	var order string
-	fmt.Print("> Enter query: ")
-	scan, err := fmt.Scan(&order)
-	if err != nil || scan != 1 {
-		log.Error("Failed to read query")
+func unaryRPC(client *orderclient.Client) {
+	order, ok := readQuery()
+	if !ok {
 		return
 	}
-	getOrderUnary(client, order)
+	log.Infof("Sending query as unary RPC: %s", order)
+	pages, err := client.Unary(context.Background(), order)
+	for _, res := range pages {
+		printResponse(res)
+	}
+	if err != nil {
+		reportError(err)
+	}
 }
 
-func serverStreamRPC(client pb.OrderManagementClient) {
-	var order string
-	fmt.Print("> Enter query: ")
-	scan, err := fmt.Scan(&order)
-	if err != nil || scan != 1 {
-		log.Error("Failed to read query")
+func serverStreamRPC(client *orderclient.Client) {
+	order, ok := readQuery()
+	if !ok {
 		return
 	}
-	getOrderServerStream(client, order)
+	log.Infof("Sending query as server stream RPC: %s", order)
+	if err := client.ServerStream(context.Background(), order, printResponse); err != nil {
+		reportError(err)
+	}
 }
 
-func clientStreamRPC(client pb.OrderManagementClient) {
+func clientStreamRPC(client *orderclient.Client) {
 	queryChan := make(chan string)
+	done := make(chan struct{})
+	var res orderclient.Response
+	var rpcErr error
+	log.Info("Sending queries as client stream RPC")
 	go func() {
-		for {
-			time.Sleep(500 * time.Millisecond)
-			var order string
-			fmt.Print("> Enter query (enter 'exit' to finish): ")
-			scan, err := fmt.Scan(&order)
-			if err != nil || scan != 1 {
-				log.Error("Failed to read query")
-				return
-			}
-			if order == "exit" {
-				break
-			}
-			queryChan <- order
-		}
-		close(queryChan)
+		res, rpcErr = client.ClientStream(context.Background(), queryChan)
+		close(done)
 	}()
-	getOrderClientStream(client, queryChan)
+	promptQueries(func(query string) bool {
+		select {
+		case queryChan <- query:
+			return true
+		case <-done:
+			return false
+		}
+	})
+	close(queryChan)
+	<-done
+	if rpcErr != nil {
+		reportError(rpcErr)
+		return
+	}
+	printResponse(res)
 }
 
-func bidirectionalStreamRPC(client pb.OrderManagementClient) {
+func bidirectionalStreamRPC(client *orderclient.Client) {
 	queryChan := make(chan string)
+	done := make(chan struct{})
+	received := make(chan struct{}, 1)
+	var rpcErr error
+	log.Info("Sending queries as bidirectional stream RPC")
 	go func() {
-		for {
-			time.Sleep(500 * time.Millisecond)
-			var order string
-			fmt.Print("> Enter query (enter 'exit' to finish): ")
-			scan, err := fmt.Scan(&order)
-			if err != nil || scan != 1 {
-				log.Error("Failed to read query")
-				return
+		rpcErr = client.BiDi(context.Background(), queryChan, func(res orderclient.Response) {
+			printResponse(res)
+			select {
+			case received <- struct{}{}:
+			default:
 			}
-			if order == "exit" {
-				break
-			}
-			queryChan <- order
-		}
-		close(queryChan)
+		})
+		close(done)
 	}()
-	GetOrderBiDiStream(client, queryChan)
+	// The next prompt is only shown once the response of the previous query
+	// has been printed, so the two never interleave.
+	promptQueries(func(query string) bool {
+		select {
+		case queryChan <- query:
+		case <-done:
+			return false
+		}
+		select {
+		case <-received:
+			return true
+		case <-done:
+			return false
+		}
+	})
+	close(queryChan)
+	<-done
+	if rpcErr != nil {
+		reportError(rpcErr)
+	}
 }
 
-func launchMenu(client pb.OrderManagementClient) {
+func launchMenu(client *orderclient.Client) {
 	for {
 		fmt.Println()
 		fmt.Println("Choose the desired communication pattern:")
@@ -213,12 +253,18 @@ func launchMenu(client pb.OrderManagementClient) {
 		fmt.Println("\t2. Server Streaming RPC")
 		fmt.Println("\t3. Client Streaming RPC")
 		fmt.Println("\t4. Bidirectional Streaming RPC")
-		fmt.Println("\t5. Exit")
+		fmt.Println("\t5. Add Order")
+		fmt.Println("\t6. Update Order")
+		fmt.Println("\t7. Delete Order")
+		fmt.Println("\t8. Get Order by ID")
+		fmt.Println("\t9. Watch Orders")
+		fmt.Println("\t10. Exit")
 
-		fmt.Print("> Enter choice: ")
-		var choice int
-		scan, err := fmt.Scan(&choice)
-		if err != nil || scan != 1 {
+		choice, err := input.readInt("> Enter choice: ")
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
 			log.Error("Failed to read choice")
 			continue
 		}
@@ -233,6 +279,16 @@ func launchMenu(client pb.OrderManagementClient) {
 		case 4:
 			bidirectionalStreamRPC(client)
 		case 5:
+			addOrder(client)
+		case 6:
+			updateOrder(client)
+		case 7:
+			deleteOrder(client)
+		case 8:
+			getOrderByID(client)
+		case 9:
+			watchRPC(client)
+		case 10:
 			return
 		default:
 			log.Error("Invalid choice")
@@ -240,31 +296,169 @@ func launchMenu(client pb.OrderManagementClient) {
 	}
 }
 
-func main() {
-	log.Info("Starting...")
+func usage() {
+	out := flag.CommandLine.Output()
+	_, _ = fmt.Fprintf(out, "Usage: %s [flags] [command [command flags] [queries...]]\n\n", os.Args[0])
+	_, _ = fmt.Fprintln(out, "Without a command, an interactive menu is launched. Commands:")
+	_, _ = fmt.Fprintln(out, "  unary, server-stream, client-stream, bidi")
+	_, _ = fmt.Fprintln(out, "        run one RPC pattern with -query, -file and -output (text or json)")
+	_, _ = fmt.Fprintln(out, "  watch")
+	_, _ = fmt.Fprintln(out, "        print the changes to the matches of one query until interrupted, with -output")
+	_, _ = fmt.Fprintln(out, "  health")
+	_, _ = fmt.Fprintln(out, "        check the server health with -service")
+	_, _ = fmt.Fprintln(out, "  replication status, replication promote")
+	_, _ = fmt.Fprintln(out, "        show the replication state of a server, or make it lead the -followers")
+	_, _ = fmt.Fprintln(out, "  raft status, raft add, raft remove")
+	_, _ = fmt.Fprintln(out, "        show the Raft state of a server, or add or remove the member -id at -address")
+	_, _ = fmt.Fprintln(out, "\nFlags:")
+	flag.PrintDefaults()
+}
 
+func main() {
 	portPtr := flag.Int("port", defaultPort, "port to listen on")
 	hostPtr := flag.String("host", defaultHost, "host to listen on")
+	modePtr := flag.String("mode", "substring", "match mode: substring, exact, case-insensitive, prefix, token or fuzzy")
+	pageSizePtr := flag.Int("page-size", 0, "number of results per page (0 returns all results at once)")
+	maxResultsPtr := flag.Int("max-results", 0, "maximum number of results per query (0 for no limit)")
+	tlsPtr := flag.Bool("tls", false, "connect with TLS (implied by -tls-ca)")
+	caPtr := flag.String("tls-ca", "", "path to the CA used to verify the server (default: system roots)")
+	certPtr := flag.String("tls-cert", "", "path to the client certificate for mutual TLS")
+	keyPtr := flag.String("tls-key", "", "path to the client private key for mutual TLS")
+	serverNamePtr := flag.String("tls-server-name", "", "server name to verify (default: host)")
+	tokenPtr := flag.String("token", "", "bearer token sent with every RPC")
+	tokenFilePtr := flag.String("token-file", "", "path to a file holding the bearer token")
+	aggregatePtr := flag.String("aggregate", "union", "how a client stream combines its matches: union, intersection, grouped or counts")
+	consistencyPtr := flag.String("consistency", "stale", "how up to date reads of a Raft cluster are: stale or linearizable")
+	windowPtr := flag.Int("window", 16, "requests of a bidirectional stream awaiting a response before sending pauses")
+	timeoutPtr := flag.Duration("timeout", 10*time.Second, "deadline of unary and server streaming calls")
+	streamTimeoutPtr := flag.Duration("stream-timeout", 0, "deadline of client and bidirectional streams (0 for none)")
+	retriesPtr := flag.Int("max-attempts", 4, "attempts of idempotent calls while the server is unavailable (1 disables retries)")
+	serviceConfigPtr := flag.String("service-config", "", "path to a gRPC service config JSON replacing the default retry policy")
+	maxBackoffPtr := flag.Duration("max-reconnect-backoff", 30*time.Second, "upper bound of the exponential backoff between reconnection attempts")
+	targetsPtr := flag.String("targets", "", "comma-separated server addresses to balance over instead of -host and -port")
+	targetsFilePtr := flag.String("targets-file", "", "path to a file listing a server address per line to balance over")
+	lbPtr := flag.String("lb", "round_robin", "load balancing policy over the servers: round_robin or pick_first")
+	flag.Usage = usage
 	flag.Parse()
+	if flag.NArg() > 0 {
+		// Keep stderr quiet for scripts, only failures are reported.
+		log.SetLevel(log.ErrorLevel)
+	}
+	log.Info("Starting...")
 	port := *portPtr
 	host := *hostPtr
 
+	mode, err := parseMatchMode(*modePtr)
+	if err != nil {
+		log.Fatalf("Invalid mode: %v", err)
+	}
+	aggregation, err := parseAggregation(*aggregatePtr)
+	if err != nil {
+		log.Fatalf("Invalid aggregation: %v", err)
+	}
+	consistency, err := parseConsistency(*consistencyPtr)
+	if err != nil {
+		log.Fatalf("Invalid consistency: %v", err)
+	}
+
+	if !slices.Contains(balancingPolicies, *lbPtr) {
+		log.Fatalf("Invalid load balancing policy: %s", *lbPtr)
+	}
+	targets := orderclient.ParseTargets(*targetsPtr)
+	if *targetsFilePtr != "" {
+		fileTargets, err := orderclient.ReadTargets(*targetsFilePtr)
+		if err != nil {
+			log.Fatalf("Failed to read targets: %v", err)
+		}
+		targets = append(targets, fileTargets...)
+	}
+
 	dialAddr := fmt.Sprintf("%s:%d", host, port)
+	var resolverOpts []grpc.DialOption
+	if len(targets) > 0 {
+		var opt grpc.DialOption
+		dialAddr, opt = orderclient.Resolve(targets)
+		resolverOpts = append(resolverOpts, opt)
+		log.Info("Balancing over servers", "servers", strings.Join(targets, ","), "policy", *lbPtr)
+	}
 	log.Infof("Dialing %s", dialAddr)
 
-	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
-	conn, err := grpc.Dial(dialAddr, opts...)
+	creds := insecure.NewCredentials()
+	if *tlsPtr || *caPtr != "" {
+		creds, err = tlsutil.ClientCredentials(*caPtr, *certPtr, *keyPtr, *serverNamePtr)
+		if err != nil {
+			log.Fatalf("Failed to load TLS credentials: %v", err)
+		}
+	}
+	set := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+	config, err := loadServiceConfig(*serviceConfigPtr, *retriesPtr, *lbPtr, set)
 	if err != nil {
-		log.Fatalf("Failed to dial: %v", err)
+		log.Fatalf("Failed to load service config: %v", err)
 	}
-	defer func() {
-		err := conn.Close()
+	reconnectBackoff := backoff.DefaultConfig
+	reconnectBackoff.MaxDelay = *maxBackoffPtr
+	opts := append(resolverOpts,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultServiceConfig(config),
+		grpc.WithConnectParams(grpc.ConnectParams{Backoff: reconnectBackoff}),
+	)
+	token := *tokenPtr
+	if *tokenFilePtr != "" {
+		secret, err := auth.ReadSecret(*tokenFilePtr)
 		if err != nil {
-			log.Fatalf("Failed to close connection: %v", err)
+			log.Fatalf("Failed to read token: %v", err)
 		}
-	}()
+		token = string(secret)
+	}
+	if token != "" {
+		secure := *tlsPtr || *caPtr != ""
+		if !secure {
+			log.Warn("Sending bearer token over a plaintext connection")
+		}
+		opts = append(opts, grpc.WithPerRPCCredentials(auth.TokenCredentials{Token: token, Secure: secure}))
+	}
+	conn, err := grpc.Dial(dialAddr, opts...)
+	if err != nil {
+		log.Fatalf("Failed to dial: %v", err)
+	}
 
-	client := pb.NewOrderManagementClient(conn)
-	launchMenu(client)
-	log.Warn("Exiting...")
+	client := orderclient.New(conn, orderclient.Options{
+		Mode:          mode,
+		PageSize:      int32(*pageSizePtr),
+		MaxResults:    int32(*maxResultsPtr),
+		Aggregation:   aggregation,
+		Consistency:   consistency,
+		Window:        *windowPtr,
+		CallTimeout:   *timeoutPtr,
+		StreamTimeout: *streamTimeoutPtr,
+	})
+	code := 0
+	switch flag.Arg(0) {
+	case "":
+		input = newLineReader()
+		launchMenu(client)
+		log.Warn("Exiting...")
+	case "health":
+		code = runHealth(conn, flag.Args()[1:])
+	case "watch":
+		code = runWatch(client, flag.Args()[1:])
+	case "replication":
+		code = runReplication(conn, flag.Args()[1:])
+	case "raft":
+		code = runRaft(conn, flag.Args()[1:])
+	default:
+		if _, ok := rpcCommands[flag.Arg(0)]; !ok {
+			log.Errorf("Unknown command: %s", flag.Arg(0))
+			code = exitUsage
+			break
+		}
+		code = runScript(client, flag.Arg(0), flag.Args()[1:])
+	}
+	if err := conn.Close(); err != nil {
+		log.Fatalf("Failed to close connection: %v", err)
+	}
+	os.Exit(code)
 }
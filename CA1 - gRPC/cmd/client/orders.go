@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/log"
+
+	"dist-grpc/pkg/orderclient"
+	pb "dist-grpc/pkg/proto"
+)
+
+func printOrder(order *pb.Order) {
+	fmt.Println("Order:")
+	fmt.Println("\tID:", order.GetId())
+	fmt.Println("\tName:", order.GetName())
+	fmt.Println("\tQuantity:", order.GetQuantity())
+	fmt.Println("\tPrice:", order.GetPrice())
+	fmt.Println("\tStatus:", order.GetStatus())
+	if order.GetCreated() != nil {
+		fmt.Println("\tCreated:", order.GetCreated().AsTime())
+	}
+}
+
+func readOrder(withID bool) (*pb.Order, bool) {
+	order := &pb.Order{}
+	if withID {
+		id, ok := readOrderID()
+		if !ok {
+			return nil, false
+		}
+		order.Id = id
+	}
+	line, err := input.readLine("> Enter order name: ")
+	if err != nil {
+		log.Error("Failed to read order name")
+		return nil, false
+	}
+	order.Name, _ = parseQuery(line)
+	quantity, err := input.readInt("> Enter quantity: ")
+	if err != nil {
+		log.Error("Failed to read quantity")
+		return nil, false
+	}
+	order.Quantity = int32(quantity)
+	if order.Price, err = input.readFloat("> Enter price: "); err != nil {
+		log.Error("Failed to read price")
+		return nil, false
+	}
+	fmt.Println("Statuses: 1. Pending 2. Processing 3. Shipped 4. Delivered 5. Cancelled")
+	orderStatus, err := input.readInt("> Enter status: ")
+	if err != nil {
+		log.Error("Failed to read status")
+		return nil, false
+	}
+	if _, ok := pb.OrderStatus_name[int32(orderStatus)]; !ok || orderStatus == 0 {
+		log.Error("Invalid status")
+		return nil, false
+	}
+	order.Status = pb.OrderStatus(orderStatus)
+	return order, true
+}
+
+func readOrderID() (string, bool) {
+	line, err := input.readLine("> Enter order id: ")
+	if err != nil || strings.TrimSpace(line) == "" {
+		log.Error("Failed to read order id")
+		return "", false
+	}
+	return strings.TrimSpace(line), true
+}
+
+func addOrder(client *orderclient.Client) {
+	order, ok := readOrder(false)
+	if !ok {
+		return
+	}
+	log.Infof("Adding order: %s", order.GetName())
+	ctx, cancel := client.CallContext(context.Background())
+	defer cancel()
+	res, err := client.RPC().AddOrder(ctx, order)
+	if err != nil {
+		reportError(err)
+		return
+	}
+	printOrder(res)
+}
+
+func updateOrder(client *orderclient.Client) {
+	order, ok := readOrder(true)
+	if !ok {
+		return
+	}
+	log.Infof("Updating order: %s", order.GetId())
+	ctx, cancel := client.CallContext(context.Background())
+	defer cancel()
+	res, err := client.RPC().UpdateOrder(ctx, order)
+	if err != nil {
+		reportError(err)
+		return
+	}
+	printOrder(res)
+}
+
+func deleteOrder(client *orderclient.Client) {
+	id, ok := readOrderID()
+	if !ok {
+		return
+	}
+	log.Infof("Deleting order: %s", id)
+	ctx, cancel := client.CallContext(context.Background())
+	defer cancel()
+	res, err := client.RPC().DeleteOrder(ctx, &pb.OrderID{Id: id})
+	if err != nil {
+		reportError(err)
+		return
+	}
+	printOrder(res)
+}
+
+func getOrderByID(client *orderclient.Client) {
+	id, ok := readOrderID()
+	if !ok {
+		return
+	}
+	log.Infof("Getting order: %s", id)
+	ctx, cancel := client.CallContext(context.Background())
+	defer cancel()
+	res, err := client.RPC().GetOrderByID(ctx, client.NewOrderID(id))
+	if err != nil {
+		reportError(err)
+		return
+	}
+	printOrder(res)
+}
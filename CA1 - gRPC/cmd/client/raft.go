@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"google.golang.org/grpc"
+
+	pb "dist-grpc/pkg/proto"
+)
+
+func printRaftStatus(st *pb.RaftStatus) {
+	fmt.Println("ID:", st.GetId())
+	fmt.Println("State:", st.GetState())
+	fmt.Println("Term:", st.GetTerm())
+	fmt.Println("Leader:", st.GetLeader())
+	fmt.Println("Commit index:", st.GetCommitIndex())
+	fmt.Println("Applied index:", st.GetAppliedIndex())
+	fmt.Println("Last index:", st.GetLastIndex())
+	fmt.Println("Snapshot index:", st.GetSnapshotIndex())
+	for _, m := range st.GetMembers() {
+		fmt.Printf("Member %s: %s\n", m.GetId(), m.GetAddress())
+	}
+}
+
+// runRaft inspects a Raft member with "status", or changes the membership of
+// its cluster with "add" and "remove", and returns the process exit code.
+func runRaft(conn *grpc.ClientConn, args []string) int {
+	fs := flag.NewFlagSet("raft", flag.ContinueOnError)
+	idPtr := fs.String("id", "", "id of the member to add or remove")
+	addressPtr := fs.String("address", "", "address the added member is reached at")
+	timeoutPtr := fs.Duration("timeout", 5*time.Second, "deadline of the call")
+	if len(args) == 0 {
+		log.Error("A subcommand must be given: status, add or remove")
+		return exitUsage
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return exitUsage
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeoutPtr)
+	defer cancel()
+	client := pb.NewRaftClient(conn)
+	member := &pb.Member{Id: *idPtr, Address: *addressPtr}
+	var st *pb.RaftStatus
+	var err error
+	switch args[0] {
+	case "status":
+		st, err = client.Status(ctx, &pb.RaftStatusRequest{})
+	case "add":
+		st, err = client.AddMember(ctx, &pb.MembershipRequest{Member: member})
+	case "remove":
+		st, err = client.RemoveMember(ctx, &pb.MembershipRequest{Member: member})
+	default:
+		log.Errorf("Unknown raft subcommand: %s", args[0])
+		return exitUsage
+	}
+	if err != nil {
+		reportError(err)
+		return 1
+	}
+	printRaftStatus(st)
+	return 0
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"google.golang.org/grpc"
+
+	"dist-grpc/pkg/orderclient"
+	pb "dist-grpc/pkg/proto"
+)
+
+func printReplicationStatus(st *pb.ReplicationStatus) {
+	fmt.Println("Role:", st.GetRole())
+	fmt.Println("Epoch:", st.GetEpoch())
+	fmt.Println("Leader:", st.GetLeader())
+	fmt.Println("Last index:", st.GetLastIndex())
+	for _, f := range st.GetFollowers() {
+		fmt.Printf("Follower %s: acked %d", f.GetAddress(), f.GetAckedIndex())
+		if f.GetError() != "" {
+			fmt.Printf(" (%s)", f.GetError())
+		}
+		fmt.Println()
+	}
+}
+
+// runReplication inspects a replica with "status", or promotes it to leader
+// of the -followers with "promote", and returns the process exit code.
+func runReplication(conn *grpc.ClientConn, args []string) int {
+	fs := flag.NewFlagSet("replication", flag.ContinueOnError)
+	followersPtr := fs.String("followers", "", "comma-separated addresses the promoted replica leads")
+	timeoutPtr := fs.Duration("timeout", 5*time.Second, "deadline of the call")
+	if len(args) == 0 {
+		log.Error("A subcommand must be given: status or promote")
+		return exitUsage
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return exitUsage
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeoutPtr)
+	defer cancel()
+	client := pb.NewReplicationClient(conn)
+	var st *pb.ReplicationStatus
+	var err error
+	switch args[0] {
+	case "status":
+		st, err = client.Status(ctx, &pb.StatusRequest{})
+	case "promote":
+		st, err = client.Promote(ctx, &pb.PromoteRequest{Followers: orderclient.ParseTargets(*followersPtr)})
+	default:
+		log.Errorf("Unknown replication subcommand: %s", args[0])
+		return exitUsage
+	}
+	if err != nil {
+		reportError(err)
+		return 1
+	}
+	printReplicationStatus(st)
+	return 0
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"dist-grpc/pkg/orderclient"
+)
+
+const (
+	exitOK    = 0
+	exitRPC   = 1
+	exitUsage = 2
+)
+
+type rpcCommand func(client *orderclient.Client, queries []string, handle func(orderclient.Response)) error
+
+var rpcCommands = map[string]rpcCommand{
+	"unary": func(client *orderclient.Client, queries []string, handle func(orderclient.Response)) error {
+		for _, query := range queries {
+			pages, err := client.Unary(context.Background(), query)
+			for _, res := range pages {
+				handle(res)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	"server-stream": func(client *orderclient.Client, queries []string, handle func(orderclient.Response)) error {
+		for _, query := range queries {
+			if err := client.ServerStream(context.Background(), query, handle); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	"client-stream": func(client *orderclient.Client, queries []string, handle func(orderclient.Response)) error {
+		res, err := client.ClientStream(context.Background(), queryChannel(queries))
+		if err != nil {
+			return err
+		}
+		handle(res)
+		return nil
+	},
+	"bidi": func(client *orderclient.Client, queries []string, handle func(orderclient.Response)) error {
+		return client.BiDi(context.Background(), queryChannel(queries), handle)
+	},
+}
+
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ", ")
+}
+
+func (l *stringList) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
+
+func queryChannel(queries []string) <-chan string {
+	queryChan := make(chan string, len(queries))
+	for _, query := range queries {
+		queryChan <- query
+	}
+	close(queryChan)
+	return queryChan
+}
+
+// readQueries reads one query per line, skipping blank lines.
+func readQueries(r io.Reader) ([]string, error) {
+	var queries []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			queries = append(queries, line)
+		}
+	}
+	return queries, scanner.Err()
+}
+
+func loadQueries(args, flagged []string, file string) ([]string, error) {
+	queries := append(append([]string(nil), flagged...), args...)
+	switch {
+	case file == "-":
+		fromStdin, err := readQueries(os.Stdin)
+		return append(queries, fromStdin...), err
+	case file != "":
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		fromFile, err := readQueries(f)
+		return append(queries, fromFile...), err
+	case len(queries) == 0:
+		return readQueries(os.Stdin)
+	}
+	return queries, nil
+}
+
+func responsePrinter(output string, w io.Writer) (func(orderclient.Response), error) {
+	switch output {
+	case "text":
+		return func(res orderclient.Response) {
+			// Lines start with the instance that served them when the
+			// server names itself.
+			prefix := ""
+			if res.Instance != "" {
+				prefix = res.Instance + "\t"
+			}
+			// Grouped and counted results say which queries, by their
+			// index among the input lines, matched them.
+			for _, group := range res.GetGroups() {
+				for _, result := range group.GetResults() {
+					_, _ = fmt.Fprintf(w, "%s%d\t%s\t%s\n", prefix, group.GetIndex(), group.GetQuery(), result)
+				}
+			}
+			if len(res.GetCounts()) > 0 {
+				for _, count := range res.GetCounts() {
+					_, _ = fmt.Fprintf(w, "%s%d\t%s\n", prefix, count.GetCount(), count.GetResult())
+				}
+				return
+			}
+			for _, result := range res.GetResults() {
+				_, _ = fmt.Fprintf(w, "%s%s\n", prefix, result)
+			}
+		}, nil
+	case "json":
+		return func(res orderclient.Response) {
+			data, err := encodeResponse(res)
+			if err != nil {
+				log.Errorf("Failed to encode response: %v", err)
+				return
+			}
+			_, _ = fmt.Fprintln(w, string(data))
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format: %s", output)
+	}
+}
+
+// encodeResponse encodes the response as JSON with the instance that served
+// it as an extra servedBy field.
+func encodeResponse(res orderclient.Response) ([]byte, error) {
+	data, err := protojson.Marshal(res.Response)
+	if err != nil || res.Instance == "" {
+		return data, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	if fields["servedBy"], err = json.Marshal(res.Instance); err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
+
+// runScript runs one RPC pattern over the queries given as arguments, with
+// -query, in a file or on stdin, and returns the process exit code.
+func runScript(client *orderclient.Client, name string, args []string) int {
+	command := rpcCommands[name]
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	var flagged stringList
+	fs.Var(&flagged, "query", "query to send (repeatable)")
+	filePtr := fs.String("file", "", "file with one query per line, - for stdin (default: stdin when no query is given)")
+	outputPtr := fs.String("output", "text", "output format: text or json")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	handle, err := responsePrinter(*outputPtr, os.Stdout)
+	if err != nil {
+		log.Error(err)
+		return exitUsage
+	}
+	queries, err := loadQueries(fs.Args(), flagged, *filePtr)
+	if err != nil {
+		log.Errorf("Failed to read queries: %v", err)
+		return exitUsage
+	}
+	if len(queries) == 0 {
+		log.Error("No queries given")
+		return exitUsage
+	}
+
+	failed := 0
+	report := func(res orderclient.Response) {
+		if st := responseStatus(res.Response); st != nil {
+			failed++
+			log.Error("Query failed", "request_id", res.GetRequestId(), "code", st.Code(), "err", st.Message())
+			for _, v := range fieldViolations(st) {
+				log.Error("Invalid query", "field", v.GetField(), "reason", v.GetDescription())
+			}
+		}
+		handle(res)
+	}
+	if err := command(client, queries, report); err != nil {
+		reportError(err)
+		return exitRPC
+	}
+	if failed > 0 {
+		return exitRPC
+	}
+	return exitOK
+}
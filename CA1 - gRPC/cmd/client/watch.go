@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/charmbracelet/log"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"dist-grpc/pkg/orderclient"
+	pb "dist-grpc/pkg/proto"
+)
+
+func printEvent(event *pb.OrderEvent) {
+	kind := event.GetType().String()
+	if event.GetInitial() {
+		kind += " (initial)"
+	}
+	fmt.Println("Event:", kind)
+	fmt.Println("\tTimestamp:", event.GetTimestamp().AsTime())
+	fmt.Println("\tID:", event.GetOrder().GetId())
+	fmt.Println("\tName:", event.GetOrder().GetName())
+	if event.GetType() != pb.EventType_EVENT_TYPE_REMOVED {
+		fmt.Println("\tScore:", event.GetScore())
+	}
+}
+
+func watchRPC(client *orderclient.Client) {
+	query, ok := readQuery()
+	if !ok {
+		return
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	fmt.Println("Watching, press Ctrl+C to stop")
+	if err := client.Watch(ctx, query, printEvent); err != nil {
+		reportError(err)
+	}
+}
+
+// runWatch prints the events of one query until interrupted and returns the
+// process exit code.
+func runWatch(client *orderclient.Client, args []string) int {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	outputPtr := fs.String("output", "text", "output format: text or json")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() != 1 {
+		log.Error("Exactly one query must be given")
+		return exitUsage
+	}
+
+	var handle func(*pb.OrderEvent)
+	switch *outputPtr {
+	case "text":
+		handle = func(event *pb.OrderEvent) {
+			fmt.Println(event.GetType(), event.GetOrder().GetName())
+		}
+	case "json":
+		handle = func(event *pb.OrderEvent) {
+			data, err := protojson.Marshal(event)
+			if err != nil {
+				log.Errorf("Failed to encode event: %v", err)
+				return
+			}
+			fmt.Println(string(data))
+		}
+	default:
+		log.Errorf("Unknown output format: %s", *outputPtr)
+		return exitUsage
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := client.Watch(ctx, fs.Arg(0), handle); err != nil {
+		reportError(err)
+		return exitRPC
+	}
+	return exitOK
+}
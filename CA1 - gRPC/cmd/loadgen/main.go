@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"dist-grpc/pkg/auth"
+	pb "dist-grpc/pkg/proto"
+	"dist-grpc/pkg/tlsutil"
+)
+
+func init() {
+	log.SetPrefix("Load Gen")
+	log.SetTimeFormat(time.TimeOnly)
+}
+
+const (
+	defaultPort = 8080
+	defaultHost = "localhost"
+)
+
+var patternOrder = []string{"unary", "server-stream", "client-stream", "bidi"}
+
+func main() {
+	portPtr := flag.Int("port", defaultPort, "port of the target server")
+	hostPtr := flag.String("host", defaultHost, "host of the target server")
+	unaryPtr := flag.Int("unary", 4, "concurrent unary sessions")
+	serverStreamPtr := flag.Int("server-stream", 1, "concurrent server streaming sessions")
+	clientStreamPtr := flag.Int("client-stream", 1, "concurrent client streaming sessions")
+	bidiPtr := flag.Int("bidi", 1, "concurrent bidirectional streaming sessions")
+	durationPtr := flag.Duration("duration", 10*time.Second, "how long sessions keep starting new calls")
+	queriesPtr := flag.String("queries", "", "path to the queries, one per line with an optional weight and tab before it (default: built-in queries)")
+	modePtr := flag.String("mode", "substring", "match mode: substring, exact, case-insensitive, prefix, token or fuzzy")
+	pageSizePtr := flag.Int("page-size", 0, "number of results per page (0 returns all results at once)")
+	maxResultsPtr := flag.Int("max-results", 0, "maximum number of results per query (0 for no limit)")
+	streamQueriesPtr := flag.Int("stream-queries", 10, "requests sent on each client or bidirectional stream")
+	windowPtr := flag.Int("window", 4, "requests of a bidirectional stream awaiting a response before sending pauses")
+	timeoutPtr := flag.Duration("timeout", 10*time.Second, "deadline of each call or stream")
+	connsPtr := flag.Int("conns", 1, "connections the sessions are spread over")
+	seedPtr := flag.Int64("seed", 1, "seed of the query picks")
+	outputPtr := flag.String("output", "text", "report format: text or json")
+	caPtr := flag.String("tls-ca", "", "path to the CA used to verify the server (default: plaintext)")
+	tokenPtr := flag.String("token", "", "bearer token sent with every RPC")
+	flag.Parse()
+	if *outputPtr != "text" && *outputPtr != "json" {
+		log.Fatalf("Invalid output: %s", *outputPtr)
+	}
+
+	mode, ok := pb.MatchMode_value["MATCH_MODE_"+strings.ToUpper(strings.ReplaceAll(*modePtr, "-", "_"))]
+	if !ok {
+		log.Fatalf("Invalid mode: %s", *modePtr)
+	}
+	queries := uniform(defaultQueries)
+	if *queriesPtr != "" {
+		var err error
+		if queries, err = loadQueries(*queriesPtr); err != nil {
+			log.Fatalf("Failed to load queries: %v", err)
+		}
+	}
+	w := workload{
+		mode:          pb.MatchMode(mode),
+		pageSize:      int32(*pageSizePtr),
+		maxResults:    int32(*maxResultsPtr),
+		streamQueries: *streamQueriesPtr,
+		window:        *windowPtr,
+		timeout:       *timeoutPtr,
+	}
+
+	target := fmt.Sprintf("%s:%d", *hostPtr, *portPtr)
+	creds := insecure.NewCredentials()
+	if *caPtr != "" {
+		var err error
+		if creds, err = tlsutil.ClientCredentials(*caPtr, "", "", ""); err != nil {
+			log.Fatalf("Failed to load TLS credentials: %v", err)
+		}
+	}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if *tokenPtr != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(auth.TokenCredentials{Token: *tokenPtr, Secure: *caPtr != ""}))
+	}
+	clients := make([]pb.OrderManagementClient, max(*connsPtr, 1))
+	for i := range clients {
+		conn, err := grpc.Dial(target, opts...)
+		if err != nil {
+			log.Fatalf("Failed to dial: %v", err)
+		}
+		defer conn.Close()
+		clients[i] = pb.NewOrderManagementClient(conn)
+	}
+
+	sessions := map[string]int{
+		"unary":         *unaryPtr,
+		"server-stream": *serverStreamPtr,
+		"client-stream": *clientStreamPtr,
+		"bidi":          *bidiPtr,
+	}
+	// Ctrl+C ends the test early and still reports it.
+	stop, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+	stop, cancelTimeout := context.WithTimeout(stop, *durationPtr)
+	defer cancelTimeout()
+
+	log.Info("Starting load", "target", target, "duration", *durationPtr, "sessions", sessions)
+	var recorders []*recorder
+	var wg sync.WaitGroup
+	started := time.Now()
+	n := 0
+	for _, name := range patternOrder {
+		if sessions[name] <= 0 {
+			continue
+		}
+		rec := newRecorder(name, sessions[name])
+		recorders = append(recorders, rec)
+		for i := 0; i < sessions[name]; i++ {
+			s := &session{
+				client:   clients[n%len(clients)],
+				queries:  queries,
+				rand:     rand.New(rand.NewSource(*seedPtr + int64(n))),
+				workload: w,
+				rec:      rec,
+			}
+			n++
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.run(stop, patterns[name])
+			}()
+		}
+	}
+	wg.Wait()
+	elapsed := time.Since(started)
+
+	rep := report{Target: target, Started: started, Duration: elapsed.Seconds()}
+	for _, rec := range recorders {
+		rep.Patterns = append(rep.Patterns, rec.report(elapsed))
+	}
+	var err error
+	if *outputPtr == "json" {
+		err = rep.writeJSON(os.Stdout)
+	} else {
+		err = rep.writeText(os.Stdout)
+	}
+	if err != nil {
+		log.Fatalf("Failed to write report: %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var defaultQueries = []string{"apple", "banana", "cherry", "red", "green apple", "pie"}
+
+// distribution picks queries at random in proportion to their weights.
+type distribution struct {
+	queries    []string
+	cumulative []int
+}
+
+func uniform(queries []string) *distribution {
+	d := &distribution{}
+	for _, query := range queries {
+		d.add(query, 1)
+	}
+	return d
+}
+
+func (d *distribution) add(query string, weight int) {
+	total := weight
+	if n := len(d.cumulative); n > 0 {
+		total += d.cumulative[n-1]
+	}
+	d.queries = append(d.queries, query)
+	d.cumulative = append(d.cumulative, total)
+}
+
+func (d *distribution) pick(r *rand.Rand) string {
+	n := r.Intn(d.cumulative[len(d.cumulative)-1])
+	return d.queries[sort.SearchInts(d.cumulative, n+1)]
+}
+
+// loadQueries reads a query per line. A line may start with a positive
+// weight followed by a tab, "5<TAB>apple" being picked five times as often
+// as a line without one. Blank lines and lines starting with # are skipped.
+func loadQueries(path string) (*distribution, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	d := &distribution{}
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		weight := 1
+		if w, query, ok := strings.Cut(text, "\t"); ok {
+			weight, err = strconv.Atoi(strings.TrimSpace(w))
+			if err != nil || weight <= 0 {
+				return nil, fmt.Errorf("line %d: invalid weight %q", line, w)
+			}
+			text = strings.TrimSpace(query)
+		}
+		d.add(text, weight)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(d.queries) == 0 {
+		return nil, fmt.Errorf("no queries in %s", path)
+	}
+	return d, nil
+}
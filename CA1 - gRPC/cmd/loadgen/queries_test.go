@@ -0,0 +1,81 @@
+package main
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDistributionPicksByWeight(t *testing.T) {
+	d := &distribution{}
+	d.add("apple", 1)
+	d.add("banana", 3)
+	d.add("cherry", 6)
+	r := rand.New(rand.NewSource(1))
+	picks := make(map[string]int)
+	const n = 100000
+	for i := 0; i < n; i++ {
+		picks[d.pick(r)]++
+	}
+	for query, weight := range map[string]float64{"apple": 0.1, "banana": 0.3, "cherry": 0.6} {
+		if share := float64(picks[query]) / n; share < weight-0.01 || share > weight+0.01 {
+			t.Errorf("%s picked %.3f of the time, want %.1f", query, share, weight)
+		}
+	}
+}
+
+func TestUniform(t *testing.T) {
+	d := uniform([]string{"apple", "banana"})
+	r := rand.New(rand.NewSource(1))
+	picks := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		picks[d.pick(r)]++
+	}
+	if len(picks) != 2 || picks["apple"] < 400 || picks["banana"] < 400 {
+		t.Errorf("picked %v, want both about equally", picks)
+	}
+}
+
+func TestLoadQueries(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		queries    []string
+		cumulative []int
+		wantErr    bool
+	}{
+		{"plain", "apple\nbanana\n", []string{"apple", "banana"}, []int{1, 2}, false},
+		{"weights", "# weighted\n5\tapple\n\n2\tgreen apple\ncherry\n", []string{"apple", "green apple", "cherry"}, []int{5, 7, 8}, false},
+		{"zero weight", "0\tapple\n", nil, nil, true},
+		{"bad weight", "x\tapple\n", nil, nil, true},
+		{"only comments", "# nothing\n\n", nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "queries")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			d, err := loadQueries(path)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("got %+v, want an error", d)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(d.queries, "|") != strings.Join(tt.queries, "|") || len(d.cumulative) != len(tt.cumulative) {
+				t.Fatalf("got %+v, want queries %v with weights %v", d, tt.queries, tt.cumulative)
+			}
+			for i := range tt.cumulative {
+				if d.cumulative[i] != tt.cumulative[i] {
+					t.Errorf("got cumulative weights %v, want %v", d.cumulative, tt.cumulative)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "dist-grpc/pkg/proto"
+)
+
+type workload struct {
+	mode       pb.MatchMode
+	pageSize   int32
+	maxResults int32
+	// streamQueries is how many requests a client or bidirectional stream
+	// sends before it is closed.
+	streamQueries int
+	// window is how many requests of a bidirectional stream may await
+	// their response.
+	window  int
+	timeout time.Duration
+}
+
+// session keeps calling one RPC pattern until the load test stops.
+type session struct {
+	client   pb.OrderManagementClient
+	queries  *distribution
+	rand     *rand.Rand
+	workload workload
+	rec      *recorder
+}
+
+type pattern func(s *session)
+
+var patterns = map[string]pattern{
+	"unary":         (*session).unary,
+	"server-stream": (*session).serverStream,
+	"client-stream": (*session).clientStream,
+	"bidi":          (*session).bidi,
+}
+
+// run starts operations until stop is done. Operations in flight are left
+// to finish so that stopping is not counted as cancelled calls.
+func (s *session) run(stop context.Context, op pattern) {
+	for stop.Err() == nil {
+		op(s)
+	}
+}
+
+func (s *session) request() *pb.Request {
+	return &pb.Request{
+		Query:      s.queries.pick(s.rand),
+		Mode:       s.workload.mode,
+		PageSize:   s.workload.pageSize,
+		MaxResults: s.workload.maxResults,
+	}
+}
+
+// responseCode is the code of a response that reports failed queries.
+func responseCode(res *pb.Response) codes.Code {
+	return codes.Code(res.GetStatus().GetCode())
+}
+
+func (s *session) unary() {
+	ctx, cancel := context.WithTimeout(context.Background(), s.workload.timeout)
+	defer cancel()
+	start := time.Now()
+	res, err := s.client.GetOrderUnary(ctx, s.request())
+	if err != nil {
+		s.rec.fail(status.Code(err))
+		return
+	}
+	s.rec.record(time.Since(start), 1, responseCode(res))
+}
+
+func (s *session) serverStream() {
+	ctx, cancel := context.WithTimeout(context.Background(), s.workload.timeout)
+	defer cancel()
+	start := time.Now()
+	messages := 0
+	stream, err := s.client.GetOrderServerStream(ctx, s.request())
+	for err == nil {
+		if _, err = stream.Recv(); err == nil {
+			messages++
+		}
+	}
+	if err != io.EOF {
+		s.rec.fail(status.Code(err))
+		return
+	}
+	s.rec.record(time.Since(start), messages, codes.OK)
+}
+
+func (s *session) clientStream() {
+	ctx, cancel := context.WithTimeout(context.Background(), s.workload.timeout)
+	defer cancel()
+	start := time.Now()
+	stream, err := s.client.GetOrderClientStream(ctx)
+	if err != nil {
+		s.rec.fail(status.Code(err))
+		return
+	}
+	for i := 0; i < s.workload.streamQueries; i++ {
+		// A failed send is reported by CloseAndRecv.
+		if stream.Send(s.request()) != nil {
+			break
+		}
+	}
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		s.rec.fail(status.Code(err))
+		return
+	}
+	s.rec.record(time.Since(start), 1, responseCode(res))
+}
+
+// bidi sends the requests of one stream, at most window of them awaiting a
+// response, and records each request from its send to its response.
+func (s *session) bidi() {
+	ctx, cancel := context.WithTimeout(context.Background(), s.workload.timeout)
+	defer cancel()
+	stream, err := s.client.GetOrderBiDiStream(ctx)
+	if err != nil {
+		s.rec.fail(status.Code(err))
+		return
+	}
+
+	var mu sync.Mutex
+	sent := make(map[string]time.Time)
+	credits := make(chan struct{}, max(s.workload.window, 1))
+	received := make(chan error, 1)
+	go func() {
+		for {
+			res, err := stream.Recv()
+			if err != nil {
+				received <- err
+				return
+			}
+			mu.Lock()
+			start, ok := sent[res.GetRequestId()]
+			delete(sent, res.GetRequestId())
+			mu.Unlock()
+			if ok {
+				s.rec.record(time.Since(start), 1, responseCode(res))
+			}
+			<-credits
+		}
+	}()
+
+	for i := 0; i < s.workload.streamQueries; i++ {
+		select {
+		case credits <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		req := s.request()
+		req.RequestId = strconv.Itoa(i + 1)
+		mu.Lock()
+		sent[req.RequestId] = time.Now()
+		mu.Unlock()
+		if stream.Send(req) != nil {
+			break
+		}
+	}
+	_ = stream.CloseSend()
+	err = <-received
+	if err == io.EOF {
+		return
+	}
+	// Requests left without a response failed with the stream.
+	mu.Lock()
+	defer mu.Unlock()
+	for range sent {
+		s.rec.fail(status.Code(err))
+	}
+	if len(sent) == 0 {
+		s.rec.fail(status.Code(err))
+	}
+}
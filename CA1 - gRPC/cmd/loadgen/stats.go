@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+// recorder collects the outcome of every operation of one RPC pattern. An
+// operation is a unary call, a whole server or client stream, or one
+// request of a bidirectional stream.
+type recorder struct {
+	pattern  string
+	sessions int
+
+	mu         sync.Mutex
+	operations int
+	latencies  []time.Duration
+	errors     map[codes.Code]int
+	messages   int
+}
+
+func newRecorder(pattern string, sessions int) *recorder {
+	return &recorder{pattern: pattern, sessions: sessions, errors: make(map[codes.Code]int)}
+}
+
+// record adds an operation that received the given number of responses and
+// ended with code.
+func (r *recorder) record(latency time.Duration, messages int, code codes.Code) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.operations++
+	r.latencies = append(r.latencies, latency)
+	r.messages += messages
+	if code != codes.OK {
+		r.errors[code]++
+	}
+}
+
+// fail adds an operation that ended with an error. It takes no latency
+// sample, so that the latencies only describe answered operations rather
+// than how fast calls fail.
+func (r *recorder) fail(code codes.Code) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.operations++
+	r.errors[code]++
+}
+
+type latencyReport struct {
+	Mean float64 `json:"mean_ms"`
+	P50  float64 `json:"p50_ms"`
+	P95  float64 `json:"p95_ms"`
+	P99  float64 `json:"p99_ms"`
+	Max  float64 `json:"max_ms"`
+}
+
+type patternReport struct {
+	Pattern    string         `json:"pattern"`
+	Sessions   int            `json:"sessions"`
+	Operations int            `json:"operations"`
+	Messages   int            `json:"messages"`
+	Errors     map[string]int `json:"errors"`
+	Throughput float64        `json:"throughput"`
+	Latency    latencyReport  `json:"latency"`
+}
+
+type report struct {
+	Target   string          `json:"target"`
+	Started  time.Time       `json:"started"`
+	Duration float64         `json:"duration_s"`
+	Patterns []patternReport `json:"patterns"`
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// percentile reads the nearest-rank percentile of sorted latencies.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p*float64(len(sorted))/100)) - 1
+	return sorted[min(max(rank, 0), len(sorted)-1)]
+}
+
+func (r *recorder) report(elapsed time.Duration) patternReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sorted := slices.Clone(r.latencies)
+	slices.Sort(sorted)
+	var total time.Duration
+	for _, latency := range sorted {
+		total += latency
+	}
+	pr := patternReport{
+		Pattern:    r.pattern,
+		Sessions:   r.sessions,
+		Operations: r.operations,
+		Messages:   r.messages,
+		Errors:     make(map[string]int),
+		Throughput: float64(r.operations) / elapsed.Seconds(),
+		Latency: latencyReport{
+			P50: milliseconds(percentile(sorted, 50)),
+			P95: milliseconds(percentile(sorted, 95)),
+			P99: milliseconds(percentile(sorted, 99)),
+			Max: milliseconds(percentile(sorted, 100)),
+		},
+	}
+	if len(sorted) > 0 {
+		pr.Latency.Mean = milliseconds(total / time.Duration(len(sorted)))
+	}
+	for code, n := range r.errors {
+		pr.Errors[code.String()] = n
+	}
+	return pr
+}
+
+func (pr patternReport) errorCount() int {
+	n := 0
+	for _, count := range pr.Errors {
+		n += count
+	}
+	return n
+}
+
+func (rep report) writeJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rep)
+}
+
+func (rep report) writeText(w io.Writer) error {
+	_, _ = fmt.Fprintf(w, "Target %s, %.1fs\n\n", rep.Target, rep.Duration)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	_, _ = fmt.Fprintln(tw, "pattern\tsessions\tops\terrors\tops/s\tmean ms\tp50 ms\tp95 ms\tp99 ms\tmax ms\t")
+	for _, pr := range rep.Patterns {
+		_, _ = fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%.1f\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\t\n",
+			pr.Pattern, pr.Sessions, pr.Operations, pr.errorCount(), pr.Throughput,
+			pr.Latency.Mean, pr.Latency.P50, pr.Latency.P95, pr.Latency.P99, pr.Latency.Max)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	var lines []string
+	for _, pr := range rep.Patterns {
+		for code, n := range pr.Errors {
+			lines = append(lines, fmt.Sprintf("%s\t%s\t%d\t", pr.Pattern, code, n))
+		}
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	slices.Sort(lines)
+	_, _ = fmt.Fprintln(w, "\nErrors:")
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, line := range lines {
+		_, _ = fmt.Fprintln(tw, line)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+func ms(n ...int) []time.Duration {
+	durations := make([]time.Duration, len(n))
+	for i, v := range n {
+		durations[i] = time.Duration(v) * time.Millisecond
+	}
+	return durations
+}
+
+func TestPercentile(t *testing.T) {
+	hundred := make([]time.Duration, 100)
+	for i := range hundred {
+		hundred[i] = time.Duration(i+1) * time.Millisecond
+	}
+	tests := []struct {
+		name   string
+		sorted []time.Duration
+		p      float64
+		want   time.Duration
+	}{
+		{"empty", nil, 50, 0},
+		{"single", ms(7), 0, 7 * time.Millisecond},
+		{"single p99", ms(7), 99, 7 * time.Millisecond},
+		{"p0 is the minimum", ms(1, 2, 3, 4), 0, time.Millisecond},
+		{"p50 of an even count", ms(1, 2, 3, 4), 50, 2 * time.Millisecond},
+		{"p50 of an odd count", ms(1, 2, 3, 4, 5), 50, 3 * time.Millisecond},
+		{"p100 is the maximum", ms(1, 2, 3, 4, 5), 100, 5 * time.Millisecond},
+		{"rank rounds up", ms(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12), 95, 12 * time.Millisecond},
+		{"rank rounds up below a half", ms(1, 2, 3, 4, 5, 6, 7, 8, 9, 10), 12, 2 * time.Millisecond},
+		{"p95 of 100", hundred, 95, 95 * time.Millisecond},
+		{"p99 of 100", hundred, 99, 99 * time.Millisecond},
+		{"p99.9 of 100", hundred, 99.9, 100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := percentile(tt.sorted, tt.p); got != tt.want {
+			t.Errorf("%s: p%v of %v is %v, want %v", tt.name, tt.p, tt.sorted, got, tt.want)
+		}
+	}
+}
+
+func TestRecorderReport(t *testing.T) {
+	r := newRecorder("unary", 2)
+	for _, latency := range ms(40, 10, 30, 20) {
+		r.record(latency, 2, codes.OK)
+	}
+	r.record(100*time.Millisecond, 1, codes.InvalidArgument)
+	r.fail(codes.Unavailable)
+	r.fail(codes.Unavailable)
+
+	pr := r.report(2 * time.Second)
+	want := patternReport{
+		Pattern:    "unary",
+		Sessions:   2,
+		Operations: 7,
+		Messages:   9,
+		Errors:     map[string]int{"InvalidArgument": 1, "Unavailable": 2},
+		Throughput: 3.5,
+		Latency:    latencyReport{Mean: 40, P50: 30, P95: 100, P99: 100, Max: 100},
+	}
+	if pr.Pattern != want.Pattern || pr.Sessions != want.Sessions || pr.Operations != want.Operations ||
+		pr.Messages != want.Messages || pr.Throughput != want.Throughput || pr.Latency != want.Latency {
+		t.Errorf("got %+v, want %+v", pr, want)
+	}
+	if len(pr.Errors) != len(want.Errors) || pr.errorCount() != 3 {
+		t.Errorf("got errors %v, want %v", pr.Errors, want.Errors)
+	}
+	for code, n := range want.Errors {
+		if pr.Errors[code] != n {
+			t.Errorf("got errors %v, want %v", pr.Errors, want.Errors)
+		}
+	}
+}
+
+func TestRecorderOnlyFailures(t *testing.T) {
+	r := newRecorder("bidi", 1)
+	r.fail(codes.DeadlineExceeded)
+	pr := r.report(time.Second)
+	if pr.Operations != 1 || pr.errorCount() != 1 || pr.Latency != (latencyReport{}) {
+		t.Errorf("got %+v, want one untimed failure", pr)
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	r := newRecorder("unary", 1)
+	r.record(10*time.Millisecond, 1, codes.OK)
+	r.fail(codes.Unavailable)
+	rep := report{Target: "localhost:50051", Duration: 1, Patterns: []patternReport{r.report(time.Second)}}
+	var b strings.Builder
+	if err := rep.writeText(&b); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Target localhost:50051, 1.0s", "unary", "Errors:", "Unavailable"} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("report lacks %q:\n%s", want, b.String())
+		}
+	}
+}
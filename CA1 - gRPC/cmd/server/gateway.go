@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+
+	"google.golang.org/grpc"
+
+	"dist-grpc/pkg/gateway"
+	pb "dist-grpc/pkg/proto"
+)
+
+// restGateway pairs the REST gateway with the loopback server answering
+// it, which has to keep serving until the gateway finished its requests.
+type restGateway struct {
+	http     *http.Server
+	loopback *grpc.Server
+}
+
+// newRestGateway serves the gateway in front of orderServer. The gateway
+// dials the handlers in-process, through a loopback server of its own that
+// shares the interceptors of opts. The transport security is applied by the
+// gateway, which serves HTTPS with tlsConfig, client certificates included.
+func newRestGateway(orderServer pb.OrderManagementServer, tlsConfig *tls.Config, opts ...grpc.ServerOption) (*restGateway, error) {
+	loopback := grpc.NewServer(opts...)
+	pb.RegisterOrderManagementServer(loopback, orderServer)
+	conn, err := gateway.Loopback(loopback)
+	if err != nil {
+		return nil, err
+	}
+	return &restGateway{
+		http:     &http.Server{Handler: gateway.New(pb.NewOrderManagementClient(conn)), TLSConfig: tlsConfig},
+		loopback: loopback,
+	}, nil
+}
+
+// serve serves the gateway on lis, over TLS when the HTTP server has a TLS
+// config.
+func (g *restGateway) serve(lis net.Listener) error {
+	if g.http.TLSConfig != nil {
+		return g.http.ServeTLS(lis, "", "")
+	}
+	return g.http.Serve(lis)
+}
+
+// stop stops the gateway taking requests, waits for those in flight and then
+// the loopback RPCs they left behind.
+func (g *restGateway) stop(ctx context.Context) {
+	if err := g.http.Shutdown(ctx); err != nil {
+		_ = g.http.Close()
+	}
+	g.loopback.GracefulStop()
+}
+
+func (g *restGateway) forceStop() {
+	_ = g.http.Close()
+	g.loopback.Stop()
+}
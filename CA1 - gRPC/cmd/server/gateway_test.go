@@ -0,0 +1,87 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"dist-grpc/pkg/harness"
+	"dist-grpc/pkg/matcher"
+	"dist-grpc/pkg/server"
+	"dist-grpc/pkg/tlsutil"
+)
+
+// TestGatewayTLS checks that the gateway of a server requiring client
+// certificates serves HTTPS and only to clients presenting one.
+func TestGatewayTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, err := tlsutil.GenerateCA("test CA", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverKP, err := tlsutil.GenerateLeaf(ca, "server", []string{"127.0.0.1"}, true, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientKP, err := tlsutil.GenerateLeaf(ca, "client", nil, false, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caFile := filepath.Join(dir, "ca.pem")
+	certFile, keyFile := filepath.Join(dir, "server.pem"), filepath.Join(dir, "server-key.pem")
+	if err := ca.WriteFiles(caFile, filepath.Join(dir, "ca-key.pem")); err != nil {
+		t.Fatal(err)
+	}
+	if err := serverKP.WriteFiles(certFile, keyFile); err != nil {
+		t.Fatal(err)
+	}
+	tlsConfig, err := tlsutil.ServerConfig(certFile, keyFile, caFile, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := harness.NewFakeStore(harness.Fruits...)
+	gatewayServer, err := newRestGateway(server.New(store, matcher.New(store), nil, server.Config{}), tlsConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lis := listen(t)
+	go func() {
+		_ = gatewayServer.serve(lis)
+	}()
+	defer gatewayServer.forceStop()
+	path := lis.Addr().String() + "/v1/orders?query=apple"
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Cert)
+	clientCert := tls.Certificate{Certificate: [][]byte{clientKP.DER}, PrivateKey: clientKP.Key}
+	tests := []struct {
+		name   string
+		url    string
+		config *tls.Config
+		want   int
+	}{
+		{"client certificate", "https://" + path, &tls.Config{RootCAs: roots, Certificates: []tls.Certificate{clientCert}}, http.StatusOK},
+		{"no client certificate", "https://" + path, &tls.Config{RootCAs: roots}, 0},
+		{"plaintext", "http://" + path, nil, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &http.Client{Transport: &http.Transport{TLSClientConfig: tt.config}, Timeout: harness.HandledTimeout}
+			resp, err := client.Get(tt.url)
+			if err != nil {
+				if tt.want != 0 {
+					t.Fatalf("got %v, want %d", err, tt.want)
+				}
+				return
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("got %s, want %d", resp.Status, tt.want)
+			}
+		})
+	}
+}
@@ -1,20 +1,36 @@
 package main
 
 import (
-	"context"
+	"crypto/tls"
 	"flag"
 	"fmt"
-	"io"
 	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
 	"time"
 
 	"github.com/charmbracelet/log"
 	"google.golang.org/grpc"
-	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 
+	"dist-grpc/pkg/auth"
+	"dist-grpc/pkg/catalog"
+	"dist-grpc/pkg/interceptor"
 	"dist-grpc/pkg/matcher"
+	"dist-grpc/pkg/metrics"
+	"dist-grpc/pkg/orderclient"
 	pb "dist-grpc/pkg/proto"
-	"dist-grpc/pkg/utils"
+	"dist-grpc/pkg/raft"
+	"dist-grpc/pkg/replication"
+	"dist-grpc/pkg/server"
+	"dist-grpc/pkg/tlsutil"
+	"dist-grpc/pkg/watch"
 )
 
 func init() {
@@ -27,106 +43,306 @@ const (
 	defaultHost = "localhost"
 )
 
-type orderManagementServer struct {
-	pb.UnimplementedOrderManagementServer
-}
+func main() {
+	log.Info("Starting...")
 
-func (s *orderManagementServer) GetOrderUnary(ctx context.Context, req *pb.Request) (*pb.Response, error) {
-	log.Info("Received unary request", "query", req.GetQuery())
-	res := matcher.MatchOrder(req.GetQuery())
-	log.Info("Matched orders", "orders", utils.ToString(res))
-	log.Info("Sending response", "results", utils.ToString(res))
-	return &pb.Response{
-		Results:   res,
-		Timestamp: timestamppb.Now(),
-	}, nil
-}
+	portPtr := flag.Int("port", defaultPort, "port to listen on")
+	hostPtr := flag.String("host", defaultHost, "host to listen on")
+	catalogPtr := flag.String("catalog", "", "path to a JSON order catalog (default: built-in orders)")
+	certPtr := flag.String("tls-cert", "", "path to the TLS certificate (default: plaintext)")
+	keyPtr := flag.String("tls-key", "", "path to the TLS private key")
+	caPtr := flag.String("tls-ca", "", "path to the CA used to verify client certificates")
+	clientAuthPtr := flag.Bool("client-auth", false, "require clients to present a certificate signed by the CA")
+	authPtr := flag.String("auth", "none", "caller authentication: none, token or jwt")
+	tokensPtr := flag.String("auth-tokens", "", "path to the static token file used by -auth token")
+	secretPtr := flag.String("jwt-secret", "", "path to the HMAC secret used by -auth jwt")
+	metricsAddrPtr := flag.String("metrics-addr", "", "address to serve Prometheus metrics on at /metrics (default: disabled)")
+	httpAddrPtr := flag.String("http-addr", "", "address to serve the REST/JSON gateway on at /v1/orders (default: disabled)")
+	debugPtr := flag.Bool("debug", false, "log every query and its matches")
+	reflectionPtr := flag.Bool("reflection", false, "enable gRPC server reflection")
+	watchBufferPtr := flag.Int("watch-buffer", watch.DefaultBuffer, "catalog changes buffered per watcher before a slow watcher is dropped")
+	bidiWorkersPtr := flag.Int("bidi-workers", 4, "requests of one bidirectional stream matched concurrently")
+	bidiUnorderedPtr := flag.Bool("bidi-unordered", false, "send bidirectional responses as soon as they are ready instead of in request order")
+	instancePtr := flag.String("instance-id", "", "id of this server sent to clients in the instance-id header (default: hostname:port)")
+	shardsPtr := flag.String("shards", "", "comma-separated shard addresses to coordinate instead of serving a catalog")
+	shardTimeoutPtr := flag.Duration("shard-timeout", 2*time.Second, "how long a coordinator waits for the shards before answering with partial results")
+	shardCAPtr := flag.String("shard-ca", "", "path to the CA used to verify the shards (default: plaintext)")
+	shardIndexPtr := flag.Int("shard-index", 0, "shard of the catalog this server keeps, by hash of the order id")
+	shardCountPtr := flag.Int("shard-count", 1, "number of shards the catalog is split into")
+	followersPtr := flag.String("followers", "", "comma-separated follower addresses to lead, shipping every write to them")
+	followerPtr := flag.Bool("follower", false, "start as a follower, applying the writes of a leader and rejecting direct writes")
+	advertisePtr := flag.String("advertise", "", "address replicas and clients reach this server at (default: host:port)")
+	minAcksPtr := flag.Int("min-acks", 0, "followers that must have a write before the leader acknowledges it")
+	ackTimeoutPtr := flag.Duration("ack-timeout", replication.DefaultAckTimeout, "how long the leader waits for -min-acks followers before failing a write")
+	replicationCAPtr := flag.String("replication-ca", "", "path to the CA used to verify the followers (default: plaintext)")
+	replicationTokenPtr := flag.String("replication-token", "", "bearer token the leader sends to its followers")
+	raftIDPtr := flag.String("raft-id", "", "id of this server in a Raft cluster replicating the catalog by consensus (default: disabled)")
+	raftPeersPtr := flag.String("raft-peers", "", "comma-separated id=address members the Raft cluster starts with, this server included")
+	raftJoinPtr := flag.Bool("raft-join", false, "join a running Raft cluster, once its leader adds this server, instead of starting one")
+	raftDirPtr := flag.String("raft-dir", "", "directory keeping the Raft log and snapshot across restarts (default: memory)")
+	raftElectionTimeoutPtr := flag.Duration("raft-election-timeout", raft.DefaultElectionTimeout, "how long a Raft follower waits to hear from the leader before standing for election")
+	raftHeartbeatPtr := flag.Duration("raft-heartbeat", raft.DefaultHeartbeat, "how often the Raft leader heartbeats its followers")
+	raftSnapshotThresholdPtr := flag.Int("raft-snapshot-threshold", raft.DefaultSnapshotThreshold, "Raft entries applied between snapshots of the catalog")
+	shutdownTimeoutPtr := flag.Duration("shutdown-timeout", 10*time.Second, "how long active streams may finish on shutdown before they are aborted")
+	flag.Parse()
+	if *debugPtr {
+		log.SetLevel(log.DebugLevel)
+	}
+	port := *portPtr
+	host := *hostPtr
 
-func (s *orderManagementServer) GetOrderServerStream(req *pb.Request, stream pb.OrderManagement_GetOrderServerStreamServer) error {
-	log.Info("Received server stream request", "query", req.GetQuery())
-	res := matcher.MatchOrder(req.GetQuery())
-	log.Info("Matched orders", "orders", utils.ToString(res))
-	for _, v := range res {
-		resp := pb.Response{
-			Results:   []string{v},
-			Timestamp: timestamppb.Now(),
+	config := server.Config{
+		BiDiWorkers:   *bidiWorkersPtr,
+		BiDiUnordered: *bidiUnorderedPtr,
+	}
+	var orderServer pb.OrderManagementServer
+	var hub *watch.Hub
+	var node *replication.Node
+	var raftNode *raft.Node
+	replicated := *followersPtr != "" || *followerPtr
+	if *raftIDPtr != "" && (replicated || *shardsPtr != "") {
+		log.Fatal("A Raft member cannot also be a coordinator, a leader or a follower")
+	}
+	if replicated && *shardsPtr != "" {
+		log.Fatal("A coordinator cannot be replicated, replicate its shards instead")
+	}
+	if *followersPtr != "" && *followerPtr {
+		log.Fatal("A server cannot both lead -followers and be a -follower")
+	}
+	if *shardsPtr != "" {
+		shards, err := dialShards(*shardsPtr, *shardCAPtr)
+		if err != nil {
+			log.Fatalf("Failed to dial shards: %v", err)
 		}
-		log.Info("Sending single response", "results", v)
-		if err := stream.Send(&resp); err != nil {
-			return err
+		orderServer = server.NewCoordinator(shards, *shardTimeoutPtr, config)
+		log.Info("Coordinating shards", "shards", *shardsPtr, "timeout", *shardTimeoutPtr)
+	} else {
+		var store catalog.Store
+		var err error
+		if *shardCountPtr > 1 {
+			if *shardIndexPtr < 0 || *shardIndexPtr >= *shardCountPtr {
+				log.Fatalf("Invalid shard index %d of %d shards", *shardIndexPtr, *shardCountPtr)
+			}
+			store, err = catalog.OpenShard(*catalogPtr, *shardIndexPtr, *shardCountPtr)
+			if err != nil {
+				log.Fatalf("Failed to open catalog shard: %v", err)
+			}
+			log.Info("Serving shard", "index", *shardIndexPtr, "count", *shardCountPtr)
+			if *catalogPtr != "" {
+				log.Info("Keeping the shard's orders", "path", catalog.ShardPath(*catalogPtr, *shardIndexPtr, *shardCountPtr))
+			}
+		} else if store, err = catalog.Open(*catalogPtr); err != nil {
+			log.Fatalf("Failed to open catalog: %v", err)
 		}
+		log.Info("Loaded catalog", "orders", len(store.List()))
+		if replicated {
+			dial, err := replicationDialer(*replicationCAPtr, *replicationTokenPtr)
+			if err != nil {
+				log.Fatalf("Failed to load replication credentials: %v", err)
+			}
+			self := *advertisePtr
+			if self == "" {
+				self = fmt.Sprintf("%s:%d", host, port)
+			}
+			node = replication.NewNode(store, replication.Config{
+				Self:       self,
+				MinAcks:    *minAcksPtr,
+				AckTimeout: *ackTimeoutPtr,
+				Dial:       dial,
+			})
+			if *followersPtr != "" {
+				node.Lead(orderclient.ParseTargets(*followersPtr))
+			} else {
+				log.Info("Following, writes are left to the leader", "advertise", self)
+			}
+			store = node
+		}
+		if *raftIDPtr != "" {
+			var members []*pb.Member
+			if *raftJoinPtr {
+				log.Info("Joining a Raft cluster, waiting for its leader to add this member", "id", *raftIDPtr)
+			} else {
+				if members, err = parseRaftPeers(*raftPeersPtr); err != nil {
+					log.Fatalf("Failed to parse Raft peers: %v", err)
+				}
+				if !slices.ContainsFunc(members, func(m *pb.Member) bool { return m.GetId() == *raftIDPtr }) {
+					log.Fatalf("Raft peers %q do not include this member %s", *raftPeersPtr, *raftIDPtr)
+				}
+			}
+			dial, err := raftDialer(*replicationCAPtr, *replicationTokenPtr)
+			if err != nil {
+				log.Fatalf("Failed to load replication credentials: %v", err)
+			}
+			var storage raft.Storage = raft.NewMemoryStorage()
+			if *raftDirPtr != "" {
+				if storage, err = raft.NewFileStorage(*raftDirPtr); err != nil {
+					log.Fatalf("Failed to open Raft storage: %v", err)
+				}
+			}
+			raftNode, err = raft.New(store, members, raft.Config{
+				ID:                *raftIDPtr,
+				ElectionTimeout:   *raftElectionTimeoutPtr,
+				Heartbeat:         *raftHeartbeatPtr,
+				SnapshotThreshold: *raftSnapshotThresholdPtr,
+				Storage:           storage,
+				Dial:              dial,
+			})
+			if err != nil {
+				log.Fatalf("Failed to start Raft: %v", err)
+			}
+			log.Info("Replicating the catalog with Raft", "id", *raftIDPtr, "dir", *raftDirPtr)
+			store = raftNode
+		}
+		hub = watch.NewHub(store, *watchBufferPtr)
+		orderServer = server.New(store, matcher.New(store), hub, config)
 	}
-	return nil
-}
 
-func (s *orderManagementServer) GetOrderClientStream(stream pb.OrderManagement_GetOrderClientStreamServer) error {
-	log.Info("Received client stream request")
-	var res []string
-	for {
-		req, err := stream.Recv()
-		if err == io.EOF {
-			log.Warn("Received EOF, closing stream")
-			break
+	listenAddr := fmt.Sprintf("%s:%d", host, port)
+	log.Infof("Listening on %s", listenAddr)
+	listener, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		log.Fatalf("Failed to listen: %v", err)
+	}
+	var credsOpts []grpc.ServerOption
+	var tlsConfig *tls.Config
+	if *certPtr != "" {
+		tlsConfig, err = tlsutil.ServerConfig(*certPtr, *keyPtr, *caPtr, *clientAuthPtr)
+		if err != nil {
+			log.Fatalf("Failed to load TLS credentials: %v", err)
 		}
+		credsOpts = append(credsOpts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+		log.Info("Serving with TLS", "client-auth", *clientAuthPtr)
+	} else {
+		log.Warn("Serving without TLS")
+	}
+	registry := metrics.NewRegistry()
+	serverMetrics := interceptor.NewMetrics(registry)
+	instance := *instancePtr
+	if instance == "" {
+		hostname, err := os.Hostname()
 		if err != nil {
-			return err
+			hostname = host
 		}
-		log.Info("Received request", "query", req.GetQuery())
-		list := matcher.MatchOrder(req.GetQuery())
-		log.Info("Matched orders", "orders", utils.ToString(list))
-		res = append(res, list...)
-	}
-	result := utils.RemoveDuplicates(res)
-	log.Info("Sending response", "results", utils.ToString(result))
-	return stream.SendAndClose(&pb.Response{
-		Results:   result,
-		Timestamp: timestamppb.Now(),
-	})
-}
+		instance = fmt.Sprintf("%s:%d", hostname, port)
+	}
+	log.Info("Serving as instance", "id", instance)
+	unaryInterceptors := []grpc.UnaryServerInterceptor{
+		interceptor.UnaryInstance(instance),
+		serverMetrics.UnaryServerInterceptor(),
+		interceptor.UnaryLogging(),
+		interceptor.UnaryRecovery(),
+	}
+	tracker := interceptor.NewStreamTracker()
+	streamInterceptors := []grpc.StreamServerInterceptor{
+		interceptor.StreamInstance(instance),
+		tracker.StreamServerInterceptor(),
+		serverMetrics.StreamServerInterceptor(),
+		interceptor.StreamLogging(),
+		interceptor.StreamRecovery(),
+	}
 
-func (s *orderManagementServer) GetOrderBiDiStream(stream pb.OrderManagement_GetOrderBiDiStreamServer) error {
-	log.Info("Received bidirectional stream request")
-	for {
-		req, err := stream.Recv()
-		if err == io.EOF {
-			log.Warn("Received EOF, closing stream")
-			return nil
-		}
+	authenticator, err := auth.Load(*authPtr, *tokensPtr, *secretPtr)
+	if err != nil {
+		log.Fatalf("Failed to load authenticator: %v", err)
+	}
+	peered := node != nil || raftNode != nil
+	if peered && authenticator != nil && *replicationTokenPtr == "" {
+		log.Fatal("Replicas authenticating their callers need -replication-token to authenticate each other")
+	}
+	unaryAuth, streamAuth := authInterceptors(authenticator, *replicationTokenPtr)
+	unaryInterceptors = append(unaryInterceptors, unaryAuth...)
+	streamInterceptors = append(streamInterceptors, streamAuth...)
+	if authenticator != nil {
+		log.Info("Authenticating callers", "mode", *authPtr)
+	}
+	if peered && *replicationTokenPtr != "" {
+		log.Info("Authenticating replicas with the replication token")
+	}
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+	}
+
+	var httpServers []*http.Server
+	if *metricsAddrPtr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", registry.Handler())
+		metricsServer := &http.Server{Addr: *metricsAddrPtr, Handler: mux}
+		httpServers = append(httpServers, metricsServer)
+		go func() {
+			log.Infof("Serving metrics on %s/metrics", *metricsAddrPtr)
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("Failed to serve metrics: %v", err)
+			}
+		}()
+	}
+	serverOpts := append(credsOpts, opts...)
+	if peered {
+		serverOpts = append(serverOpts, grpc.MaxRecvMsgSize(maxSnapshotSize))
+	}
+	grpcServer := grpc.NewServer(serverOpts...)
+	grpcServers := []*grpc.Server{grpcServer}
+	pb.RegisterOrderManagementServer(grpcServer, orderServer)
+	if node != nil {
+		pb.RegisterReplicationServer(grpcServer, node)
+	}
+	if raftNode != nil {
+		pb.RegisterRaftServer(grpcServer, raftNode)
+	}
+	var gatewayServer *restGateway
+	if *httpAddrPtr != "" {
+		gatewayServer, err = newRestGateway(orderServer, tlsConfig, opts...)
 		if err != nil {
-			return err
+			log.Fatalf("Failed to dial the gateway loopback: %v", err)
 		}
-		log.Info("Received request", "query", req.GetQuery())
-		res := matcher.MatchOrder(req.GetQuery())
-		log.Info("Matched orders", "orders", utils.ToString(res))
-		log.Info("Sending response", "results", utils.ToString(res))
-		err = stream.Send(&pb.Response{
-			Results:   res,
-			Timestamp: timestamppb.Now(),
-		})
+		gatewayListener, err := net.Listen("tcp", *httpAddrPtr)
 		if err != nil {
-			return err
+			log.Fatalf("Failed to listen for the gateway: %v", err)
 		}
+		go func() {
+			scheme := "http"
+			if tlsConfig != nil {
+				scheme = "https"
+			}
+			log.Infof("Serving the REST gateway on %s://%s/v1/orders", scheme, *httpAddrPtr)
+			if err := gatewayServer.serve(gatewayListener); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("Failed to serve the gateway: %v", err)
+			}
+		}()
+	}
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(pb.OrderManagement_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	if *reflectionPtr {
+		reflection.Register(grpcServer)
+		log.Info("Server reflection enabled")
 	}
-}
-
-func main() {
-	log.Info("Starting...")
 
-	portPtr := flag.Int("port", defaultPort, "port to listen on")
-	hostPtr := flag.String("host", defaultHost, "host to listen on")
-	flag.Parse()
-	port := *portPtr
-	host := *hostPtr
+	done := make(chan struct{})
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+		sig := <-signals
+		log.Warn("Shutting down...", "signal", sig)
+		// A second signal skips the drain.
+		go func() {
+			<-signals
+			log.Warn("Forcing stop")
+			forceStop(grpcServers, gatewayServer)
+		}()
+		shutdown(grpcServers, gatewayServer, healthServer, httpServers, hub, tracker, *shutdownTimeoutPtr)
+		close(done)
+	}()
 
-	listenAddr := fmt.Sprintf("%s:%d", host, port)
-	log.Infof("Listening on %s", listenAddr)
-	listener, err := net.Listen("tcp", listenAddr)
-	if err != nil {
-		log.Fatalf("Failed to listen: %v", err)
-	}
-	grpcServer := grpc.NewServer()
-	pb.RegisterOrderManagementServer(grpcServer, &orderManagementServer{})
 	if err = grpcServer.Serve(listener); err != nil {
 		log.Fatalf("Failed to serve: %v", err)
 	}
+	<-done
+	if node != nil {
+		node.Close()
+	}
+	if raftNode != nil {
+		raftNode.Close()
+	}
+	log.Warn("Stopped")
 }
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+
+	pb "dist-grpc/pkg/proto"
+)
+
+// parseRaftPeers parses the comma-separated id=address members of a Raft
+// cluster.
+func parseRaftPeers(peers string) ([]*pb.Member, error) {
+	var members []*pb.Member
+	for _, peer := range strings.Split(peers, ",") {
+		peer = strings.TrimSpace(peer)
+		if peer == "" {
+			continue
+		}
+		id, addr, ok := strings.Cut(peer, "=")
+		if !ok || id == "" || addr == "" {
+			return nil, fmt.Errorf("invalid raft peer %q, want id=address", peer)
+		}
+		members = append(members, &pb.Member{Id: id, Address: addr})
+	}
+	return members, nil
+}
+
+// raftDialer connects a Raft member to the others.
+func raftDialer(caFile, token string) (func(addr string) (pb.RaftClient, error), error) {
+	opts, err := peerDialOptions(caFile, token)
+	if err != nil {
+		return nil, err
+	}
+	return func(addr string) (pb.RaftClient, error) {
+		conn, err := grpc.Dial(addr, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial raft member %s: %w", addr, err)
+		}
+		return pb.NewRaftClient(conn), nil
+	}, nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"dist-grpc/pkg/auth"
+	pb "dist-grpc/pkg/proto"
+	"dist-grpc/pkg/tlsutil"
+)
+
+// maxSnapshotSize bounds the snapshot of the catalog a replica sends in a
+// single message, which outgrows the default 4 MB of gRPC.
+const maxSnapshotSize = 256 << 20
+
+// peerServices are the internal services only the other replicas, and the
+// operators holding their token, may call.
+var peerServices = []string{
+	"/" + pb.Replication_ServiceDesc.ServiceName + "/",
+	"/" + pb.Raft_ServiceDesc.ServiceName + "/",
+}
+
+// authInterceptors authenticate the callers of the peer services with the
+// peer token, when it is set, and the other callers with the authenticator,
+// when there is one.
+func authInterceptors(authenticator auth.Authenticator, peerToken string) ([]grpc.UnaryServerInterceptor, []grpc.StreamServerInterceptor) {
+	var unary []grpc.UnaryServerInterceptor
+	var stream []grpc.StreamServerInterceptor
+	// Load balancers probe health without credentials.
+	exempt := []string{"/" + healthpb.Health_ServiceDesc.ServiceName + "/"}
+	if peerToken != "" {
+		peers := auth.NewPeerToken(peerToken)
+		unary = append(unary, auth.UnaryPeerInterceptor(peers, peerServices...))
+		stream = append(stream, auth.StreamPeerInterceptor(peers, peerServices...))
+		exempt = append(exempt, peerServices...)
+	}
+	if authenticator != nil {
+		unary = append(unary, auth.UnaryServerInterceptor(authenticator, exempt...))
+		stream = append(stream, auth.StreamServerInterceptor(authenticator, exempt...))
+	}
+	return unary, stream
+}
+
+// peerDialOptions are the options replicas dial each other with, verified
+// with the CA and authenticated with the token when they are set.
+func peerDialOptions(caFile, token string) ([]grpc.DialOption, error) {
+	creds := insecure.NewCredentials()
+	if caFile != "" {
+		var err error
+		if creds, err = tlsutil.ClientCredentials(caFile, "", "", ""); err != nil {
+			return nil, err
+		}
+	}
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultCallOptions(grpc.MaxCallSendMsgSize(maxSnapshotSize)),
+	}
+	if token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(auth.TokenCredentials{Token: token, Secure: caFile != ""}))
+	}
+	return opts, nil
+}
+
+// replicationDialer connects a leader to its followers.
+func replicationDialer(caFile, token string) (func(addr string) (pb.ReplicationClient, error), error) {
+	opts, err := peerDialOptions(caFile, token)
+	if err != nil {
+		return nil, err
+	}
+	return func(addr string) (pb.ReplicationClient, error) {
+		conn, err := grpc.Dial(addr, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial follower %s: %w", addr, err)
+		}
+		return pb.NewReplicationClient(conn), nil
+	}, nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"dist-grpc/internal/harnesstest"
+	"dist-grpc/pkg/auth"
+	"dist-grpc/pkg/catalog"
+	"dist-grpc/pkg/matcher"
+	pb "dist-grpc/pkg/proto"
+	"dist-grpc/pkg/raft"
+	"dist-grpc/pkg/replication"
+	"dist-grpc/pkg/server"
+)
+
+// dialToken connects to addr sending the bearer token.
+func dialToken(t *testing.T, addr, token string) *grpc.ClientConn {
+	t.Helper()
+	conn, err := grpc.Dial(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithPerRPCCredentials(auth.TokenCredentials{Token: token}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func loadTokens(t *testing.T) auth.Authenticator {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "tokens")
+	if err := os.WriteFile(path, []byte("t0ken alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tokens, err := auth.LoadStaticTokens(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tokens
+}
+
+// TestReplicationPeers ships a snapshot larger than the default message size
+// of gRPC to a follower authenticating clients, and checks that its
+// Replication service only takes the replication token, which in turn
+// cannot reach the catalog.
+func TestReplicationPeers(t *testing.T) {
+	tokens := loadTokens(t)
+	follower := replication.NewNode(catalog.NewMemoryStore(nil), replication.Config{Self: "follower"})
+	defer follower.Close()
+	unary, stream := authInterceptors(tokens, "p33r")
+	grpcServer := grpc.NewServer(
+		grpc.MaxRecvMsgSize(maxSnapshotSize),
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	)
+	pb.RegisterOrderManagementServer(grpcServer, server.New(follower, matcher.New(follower), nil, server.Config{}))
+	pb.RegisterReplicationServer(grpcServer, follower)
+	lis := listen(t)
+	go func() {
+		_ = grpcServer.Serve(lis)
+	}()
+	defer grpcServer.Stop()
+	addr := lis.Addr().String()
+
+	const count = 6000
+	orders := make([]catalog.Order, count)
+	for i := range orders {
+		orders[i] = catalog.Order{ID: fmt.Sprint(i + 1), Name: strings.Repeat("x", 1024), Status: catalog.StatusPending}
+	}
+	dial, err := replicationDialer("", "p33r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	leader := replication.NewNode(catalog.NewMemoryStore(orders), replication.Config{Self: "leader", Dial: dial})
+	defer leader.Close()
+	leader.Lead([]string{addr})
+	deadline := time.Now().Add(10 * time.Second)
+	for len(follower.List()) != count {
+		if time.Now().After(deadline) {
+			t.Fatalf("follower has %d orders, want the %d of the snapshot", len(follower.List()), count)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	ctx := context.Background()
+	_, err = pb.NewReplicationClient(dialToken(t, addr, "t0ken")).Promote(ctx, &pb.PromoteRequest{})
+	harnesstest.ExpectCode(t, err, codes.Unauthenticated)
+	if _, err := pb.NewReplicationClient(dialToken(t, addr, "p33r")).Status(ctx, &pb.StatusRequest{}); err != nil {
+		t.Errorf("replica status: %v", err)
+	}
+	_, err = pb.NewOrderManagementClient(dialToken(t, addr, "p33r")).GetOrderByID(ctx, &pb.OrderID{Id: "1"})
+	harnesstest.ExpectCode(t, err, codes.Unauthenticated)
+	if _, err := pb.NewOrderManagementClient(dialToken(t, addr, "t0ken")).GetOrderByID(ctx, &pb.OrderID{Id: "1"}); err != nil {
+		t.Errorf("client read: %v", err)
+	}
+}
+
+// TestRaftPeers checks that the Raft service of a member authenticating
+// clients, membership changes included, only takes the replication token.
+func TestRaftPeers(t *testing.T) {
+	dial, err := raftDialer("", "p33r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lis := listen(t)
+	addr := lis.Addr().String()
+	store := catalog.NewMemoryStore(nil)
+	node, err := raft.New(store, []*pb.Member{{Id: "a", Address: addr}}, raft.Config{ID: "a", Dial: dial})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer node.Close()
+	unary, stream := authInterceptors(loadTokens(t), "p33r")
+	grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(unary...), grpc.ChainStreamInterceptor(stream...))
+	pb.RegisterRaftServer(grpcServer, node)
+	go func() {
+		_ = grpcServer.Serve(lis)
+	}()
+	defer grpcServer.Stop()
+
+	ctx := context.Background()
+	client := pb.NewRaftClient(dialToken(t, addr, "t0ken"))
+	_, err = client.AddMember(ctx, &pb.MembershipRequest{Member: &pb.Member{Id: "b", Address: "localhost:1"}})
+	harnesstest.ExpectCode(t, err, codes.Unauthenticated)
+	_, err = client.RequestVote(ctx, &pb.VoteRequest{Term: 100, Candidate: "b"})
+	harnesstest.ExpectCode(t, err, codes.Unauthenticated)
+	_, err = client.AppendEntries(ctx, &pb.AppendEntriesRequest{Term: 100, Leader: "b"})
+	harnesstest.ExpectCode(t, err, codes.Unauthenticated)
+	st, err := pb.NewRaftClient(dialToken(t, addr, "p33r")).Status(ctx, &pb.RaftStatusRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.GetTerm() >= 100 || len(st.GetMembers()) != 1 {
+		t.Errorf("got term %d and members %v, want the calls of the client left out", st.GetTerm(), st.GetMembers())
+	}
+}
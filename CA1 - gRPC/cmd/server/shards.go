@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	pb "dist-grpc/pkg/proto"
+	"dist-grpc/pkg/server"
+	"dist-grpc/pkg/tlsutil"
+)
+
+// dialShards connects to every shard of a comma-separated address list, in
+// the order that decides which orders each shard holds.
+func dialShards(list, caFile string) ([]server.Shard, error) {
+	creds := insecure.NewCredentials()
+	if caFile != "" {
+		var err error
+		if creds, err = tlsutil.ClientCredentials(caFile, "", "", ""); err != nil {
+			return nil, err
+		}
+	}
+	var shards []server.Shard
+	for _, addr := range strings.Split(list, ",") {
+		addr = strings.TrimSpace(addr)
+		if addr == "" {
+			continue
+		}
+		conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(creds))
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial shard %s: %w", addr, err)
+		}
+		shards = append(shards, server.Shard{Name: addr, Client: pb.NewOrderManagementClient(conn)})
+	}
+	if len(shards) == 0 {
+		return nil, fmt.Errorf("no shard addresses in %q", list)
+	}
+	return shards, nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+
+	"dist-grpc/pkg/interceptor"
+	"dist-grpc/pkg/watch"
+)
+
+// forceStop cuts off every RPC and gateway request still running.
+func forceStop(grpcServers []*grpc.Server, gateway *restGateway) {
+	for _, s := range grpcServers {
+		s.Stop()
+	}
+	if gateway != nil {
+		gateway.forceStop()
+	}
+}
+
+// shutdown marks the server as not serving, stops accepting new RPCs and
+// gateway requests and lets the active ones finish until the timeout, after
+// which the remaining ones are cut off. The other HTTP servers are closed
+// last, so that metrics can be scraped during the drain.
+func shutdown(grpcServers []*grpc.Server, gateway *restGateway, healthServer *health.Server, httpServers []*http.Server, hub *watch.Hub, tracker *interceptor.StreamTracker, timeout time.Duration) {
+	healthServer.Shutdown()
+	// Watches never finish on their own, so they are ended with
+	// codes.Unavailable rather than left to run into the deadline. A
+	// coordinator has no hub, its watches end with those of its shards.
+	if hub != nil {
+		hub.Close()
+	}
+	inFlight := tracker.Active()
+	log.Info("Draining streams", "active", inFlight, "timeout", timeout)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	stopped := make(chan struct{})
+	go func() {
+		var wg sync.WaitGroup
+		for _, s := range grpcServers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.GracefulStop()
+			}()
+		}
+		if gateway != nil {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				gateway.stop(ctx)
+			}()
+		}
+		wg.Wait()
+		close(stopped)
+	}()
+
+	aborted := 0
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		aborted = tracker.Active()
+		log.Warn("Drain deadline exceeded, forcing stop", "remaining", aborted)
+		forceStop(grpcServers, gateway)
+		<-stopped
+	}
+	log.Info("Streams drained", "drained", max(inFlight-aborted, 0), "aborted", aborted)
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for _, s := range httpServers {
+		_ = s.Shutdown(ctx)
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/status"
+
+	"dist-grpc/pkg/harness"
+	"dist-grpc/pkg/interceptor"
+	"dist-grpc/pkg/matcher"
+	pb "dist-grpc/pkg/proto"
+	"dist-grpc/pkg/server"
+)
+
+func dial(t *testing.T, addr string) pb.OrderManagementClient {
+	t.Helper()
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return pb.NewOrderManagementClient(conn)
+}
+
+func listen(t *testing.T) net.Listener {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return lis
+}
+
+// TestShutdownDrains checks that RPCs and gateway requests in flight when the
+// shutdown starts complete, while new ones are refused at the door instead of
+// reaching a gateway whose loopback server is already stopping.
+func TestShutdownDrains(t *testing.T) {
+	store := harness.NewFakeStore(harness.Fruits...)
+	store.ListDelay = time.Second
+	orderServer := server.New(store, matcher.NewLinear(store), nil, server.Config{})
+	tracker := interceptor.NewStreamTracker()
+	opts := []grpc.ServerOption{grpc.ChainStreamInterceptor(tracker.StreamServerInterceptor())}
+
+	grpcServer := grpc.NewServer(opts...)
+	pb.RegisterOrderManagementServer(grpcServer, orderServer)
+	grpcLis := listen(t)
+	go func() {
+		_ = grpcServer.Serve(grpcLis)
+	}()
+	gatewayServer, err := newRestGateway(orderServer, nil, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpLis := listen(t)
+	go func() {
+		_ = gatewayServer.serve(httpLis)
+	}()
+	grpcAddr, gatewayURL := grpcLis.Addr().String(), "http://"+httpLis.Addr().String()+"/v1/orders?query=apple"
+
+	rpcDone := make(chan error, 1)
+	var results []string
+	go func() {
+		res, err := dial(t, grpcAddr).GetOrderUnary(context.Background(), &pb.Request{Query: "apple"})
+		results = res.GetResults()
+		rpcDone <- err
+	}()
+	httpDone := make(chan error, 1)
+	go func() {
+		resp, err := http.Get(gatewayURL)
+		if err == nil {
+			_ = resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				err = errors.New(resp.Status)
+			}
+		}
+		httpDone <- err
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for store.Lists() < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("requests did not reach the handler")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		shutdown([]*grpc.Server{grpcServer}, gatewayServer, health.NewServer(), nil, nil, tracker, 5*time.Second)
+		close(stopped)
+	}()
+
+	// New calls have to fail without reaching the handler: a gRPC call with
+	// Unavailable and a gateway request before it gets any response.
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}, Timeout: 100 * time.Millisecond}
+	rpcRefused, httpRefused := false, false
+	for !rpcRefused || !httpRefused {
+		if time.Now().After(deadline) {
+			t.Fatalf("new calls were not refused: gRPC %v, gateway %v", rpcRefused, httpRefused)
+		}
+		if !rpcRefused {
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			_, err := dial(t, grpcAddr).GetOrderUnary(ctx, &pb.Request{Query: "apple"})
+			cancel()
+			rpcRefused = status.Code(err) == codes.Unavailable
+		}
+		if !httpRefused {
+			resp, err := client.Get(gatewayURL)
+			if err == nil {
+				t.Fatalf("gateway answered a request during the shutdown with %s", resp.Status)
+			}
+			var netErr net.Error
+			httpRefused = !errors.As(err, &netErr) || !netErr.Timeout()
+		}
+	}
+
+	if err := <-rpcDone; err != nil {
+		t.Errorf("in-flight RPC failed: %v", err)
+	} else if !slices.Equal(results, harness.AppleResults) {
+		t.Errorf("in-flight RPC got %v, want %v", results, harness.AppleResults)
+	}
+	if err := <-httpDone; err != nil {
+		t.Errorf("in-flight gateway request failed: %v", err)
+	}
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("shutdown did not return")
+	}
+}
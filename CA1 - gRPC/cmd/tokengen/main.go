@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"dist-grpc/pkg/auth"
+)
+
+func init() {
+	log.SetPrefix("Token Gen")
+	log.SetTimeFormat(time.TimeOnly)
+}
+
+func main() {
+	secretPtr := flag.String("jwt-secret", "", "path to the HMAC secret shared with the server")
+	subjectPtr := flag.String("subject", "", "subject (caller identity) of the token")
+	issuerPtr := flag.String("issuer", "dist-grpc", "issuer of the token")
+	ttlPtr := flag.Duration("ttl", 24*time.Hour, "lifetime of the token (0 for no expiry)")
+	flag.Parse()
+
+	if *subjectPtr == "" {
+		log.Fatal("A subject is required")
+	}
+	secret, err := auth.ReadSecret(*secretPtr)
+	if err != nil {
+		log.Fatalf("Failed to read secret: %v", err)
+	}
+
+	now := time.Now()
+	claims := auth.Claims{
+		Subject:  *subjectPtr,
+		Issuer:   *issuerPtr,
+		IssuedAt: now.Unix(),
+	}
+	if *ttlPtr > 0 {
+		claims.ExpiresAt = now.Add(*ttlPtr).Unix()
+	}
+	token, err := auth.NewJWT(secret).Sign(claims)
+	if err != nil {
+		log.Fatalf("Failed to sign token: %v", err)
+	}
+	fmt.Println(token)
+}
@@ -0,0 +1,44 @@
+// Package harnesstest holds the expectations the tests check against a
+// harness, kept apart so that only tests link the testing package.
+package harnesstest
+
+import (
+	"slices"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"dist-grpc/pkg/harness"
+)
+
+// ExpectCode fails the test unless err has the code.
+func ExpectCode(t testing.TB, err error, want codes.Code) {
+	t.Helper()
+	if got := status.Code(err); got != want {
+		t.Fatalf("got code %s, want %s: %v", got, want, err)
+	}
+}
+
+// ExpectHandled waits for the server to finish the RPC and fails the test
+// unless its handler returned the code.
+func ExpectHandled(t testing.TB, h *harness.Harness, want codes.Code) harness.Handled {
+	t.Helper()
+	handled, err := h.WaitHandled(harness.HandledTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := status.Code(handled.Err); got != want {
+		t.Fatalf("server returned %s, want %s: %v", got, want, handled.Err)
+	}
+	return handled
+}
+
+// ExpectResults fails the test unless the results are the wanted ones, in
+// the same order.
+func ExpectResults(t testing.TB, got []string, want ...string) {
+	t.Helper()
+	if !slices.Equal(got, want) {
+		t.Fatalf("got results %v, want %v", got, want)
+	}
+}
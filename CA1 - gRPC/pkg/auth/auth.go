@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+type Identity struct {
+	Subject string
+	Method  string
+}
+
+func (id Identity) String() string {
+	return id.Method + ":" + id.Subject
+}
+
+type Authenticator interface {
+	Authenticate(token string) (Identity, error)
+}
+
+type identityKey struct{}
+
+func NewContext(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+func FromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(Identity)
+	return id, ok
+}
+
+// Caller names the authenticated caller of ctx for log lines.
+func Caller(ctx context.Context) string {
+	if id, ok := FromContext(ctx); ok {
+		return id.String()
+	}
+	return "anonymous"
+}
+
+// Load builds the authenticator for the given mode: "none" disables
+// authentication, "token" reads a static token file and "jwt" a shared secret.
+func Load(mode, tokenFile, secretFile string) (Authenticator, error) {
+	switch mode {
+	case "", "none":
+		return nil, nil
+	case "token":
+		return LoadStaticTokens(tokenFile)
+	case "jwt":
+		secret, err := ReadSecret(secretFile)
+		if err != nil {
+			return nil, err
+		}
+		return NewJWT(secret), nil
+	default:
+		return nil, fmt.Errorf("unknown auth mode: %s", mode)
+	}
+}
+
+func ReadSecret(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret: %w", err)
+	}
+	secret := bytes.TrimSpace(data)
+	if len(secret) == 0 {
+		return nil, errors.New("secret is empty")
+	}
+	return secret, nil
+}
+
+type identitySlotKey struct{}
+
+// WithIdentitySlot lets an interceptor that runs before authentication learn
+// the identity established further down the chain once the call returns.
+func WithIdentitySlot(ctx context.Context) (context.Context, *Identity) {
+	id := &Identity{}
+	return context.WithValue(ctx, identitySlotKey{}, id), id
+}
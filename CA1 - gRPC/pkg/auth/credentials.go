@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"context"
+)
+
+// TokenCredentials attaches a bearer token to every RPC. Secure should only be
+// false for local development over plaintext connections.
+type TokenCredentials struct {
+	Token  string
+	Secure bool
+}
+
+func (c TokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + c.Token}, nil
+}
+
+func (c TokenCredentials) RequireTransportSecurity() bool {
+	return c.Secure
+}
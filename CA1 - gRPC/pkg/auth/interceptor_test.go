@@ -0,0 +1,212 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func loadTokens(t *testing.T, content string) *StaticTokens {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "tokens")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	st, err := LoadStaticTokens(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return st
+}
+
+func TestStaticTokens(t *testing.T) {
+	st := loadTokens(t, "# callers\nt0ken alice\n\n  s3cret   bob  \n")
+	tests := []struct {
+		token string
+		want  string
+	}{
+		{"t0ken", "alice"},
+		{"s3cret", "bob"},
+		{"alice", ""},
+		{"t0ken ", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		id, err := st.Authenticate(tt.token)
+		if tt.want == "" {
+			if !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("%q: got %v with error %v, want %v", tt.token, id, err, ErrInvalidToken)
+			}
+			continue
+		}
+		if err != nil || id != (Identity{Subject: tt.want, Method: "token"}) {
+			t.Errorf("%q: got %v with error %v, want token:%s", tt.token, id, err, tt.want)
+		}
+	}
+}
+
+func TestLoadStaticTokensRejectsMalformedLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens")
+	if err := os.WriteFile(path, []byte("t0ken alice\nlonely\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadStaticTokens(path); err == nil {
+		t.Error("accepted a line without a subject")
+	}
+}
+
+// authCases are the incoming metadata the interceptors must accept or
+// reject, for a static token authenticator knowing "t0ken" as alice.
+var authCases = []struct {
+	name string
+	md   metadata.MD
+	want codes.Code
+}{
+	{"valid", metadata.Pairs("authorization", "Bearer t0ken"), codes.OK},
+	{"lower case scheme", metadata.Pairs("authorization", "bearer t0ken"), codes.OK},
+	{"padded token", metadata.Pairs("authorization", "Bearer  t0ken "), codes.OK},
+	{"no metadata", nil, codes.Unauthenticated},
+	{"no authorization", metadata.Pairs("other", "Bearer t0ken"), codes.Unauthenticated},
+	{"empty authorization", metadata.Pairs("authorization", ""), codes.Unauthenticated},
+	{"scheme only", metadata.Pairs("authorization", "Bearer"), codes.Unauthenticated},
+	{"basic scheme", metadata.Pairs("authorization", "Basic dDBrZW4="), codes.Unauthenticated},
+	{"token without scheme", metadata.Pairs("authorization", "t0ken"), codes.Unauthenticated},
+	{"unknown token", metadata.Pairs("authorization", "Bearer guess"), codes.Unauthenticated},
+}
+
+func incoming(md metadata.MD) context.Context {
+	if md == nil {
+		return context.Background()
+	}
+	return metadata.NewIncomingContext(context.Background(), md)
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	intercept := UnaryServerInterceptor(loadTokens(t, "t0ken alice\n"), "/grpc.health.")
+	for _, tt := range authCases {
+		t.Run(tt.name, func(t *testing.T) {
+			var caller string
+			handler := func(ctx context.Context, req any) (any, error) {
+				caller = Caller(ctx)
+				return req, nil
+			}
+			_, err := intercept(incoming(tt.md), nil, &grpc.UnaryServerInfo{FullMethod: "/orders.Orders/Get"}, handler)
+			if status.Code(err) != tt.want {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+			if tt.want == codes.OK && caller != "token:alice" {
+				t.Errorf("handler saw caller %s, want token:alice", caller)
+			}
+			if tt.want != codes.OK && caller != "" {
+				t.Errorf("handler ran for a rejected call")
+			}
+		})
+	}
+}
+
+func TestUnaryServerInterceptorExempt(t *testing.T) {
+	intercept := UnaryServerInterceptor(loadTokens(t, "t0ken alice\n"), "/grpc.health.")
+	var caller string
+	handler := func(ctx context.Context, req any) (any, error) {
+		caller = Caller(ctx)
+		return req, nil
+	}
+	_, err := intercept(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, handler)
+	if err != nil || caller != "anonymous" {
+		t.Errorf("exempt call got caller %s with error %v", caller, err)
+	}
+}
+
+type fakeStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	intercept := StreamServerInterceptor(loadTokens(t, "t0ken alice\n"), "/grpc.health.")
+	for _, tt := range authCases {
+		t.Run(tt.name, func(t *testing.T) {
+			var caller string
+			handler := func(srv any, ss grpc.ServerStream) error {
+				caller = Caller(ss.Context())
+				return nil
+			}
+			err := intercept(nil, &fakeStream{ctx: incoming(tt.md)}, &grpc.StreamServerInfo{FullMethod: "/orders.Orders/Watch"}, handler)
+			if status.Code(err) != tt.want {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+			if tt.want == codes.OK && caller != "token:alice" {
+				t.Errorf("handler saw caller %s, want token:alice", caller)
+			}
+			if tt.want != codes.OK && caller != "" {
+				t.Errorf("handler ran for a rejected call")
+			}
+		})
+	}
+}
+
+func TestIdentitySlot(t *testing.T) {
+	intercept := StreamServerInterceptor(loadTokens(t, "t0ken alice\n"))
+	ctx, slot := WithIdentitySlot(incoming(metadata.Pairs("authorization", "Bearer t0ken")))
+	err := intercept(nil, &fakeStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/orders.Orders/Watch"}, func(any, grpc.ServerStream) error {
+		return nil
+	})
+	if err != nil || slot.String() != "token:alice" {
+		t.Errorf("slot holds %v with error %v, want token:alice", slot, err)
+	}
+}
+
+// TestPeerInterceptors checks that only the peer token reaches the peer
+// methods, while the other methods are left to the client authentication.
+func TestPeerInterceptors(t *testing.T) {
+	peers := NewPeerToken("p33r")
+	unary := UnaryPeerInterceptor(peers, "/Replication/")
+	stream := StreamPeerInterceptor(peers, "/Replication/")
+	tests := []struct {
+		name   string
+		method string
+		token  string
+		want   codes.Code
+		caller string
+	}{
+		{"peer", "/Replication/Append", "p33r", codes.OK, "peer:replica"},
+		{"client token", "/Replication/Append", "t0ken", codes.Unauthenticated, ""},
+		{"no token", "/Replication/Promote", "", codes.Unauthenticated, ""},
+		{"other method", "/OrderManagement/AddOrder", "", codes.OK, "anonymous"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := metadata.Pairs()
+			if tt.token != "" {
+				md = metadata.Pairs("authorization", "Bearer "+tt.token)
+			}
+			var caller string
+			_, err := unary(incoming(md), nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, func(ctx context.Context, req any) (any, error) {
+				caller = Caller(ctx)
+				return req, nil
+			})
+			if status.Code(err) != tt.want || caller != tt.caller {
+				t.Errorf("unary: got caller %q with %v, want %q with %v", caller, err, tt.caller, tt.want)
+			}
+			caller = ""
+			err = stream(nil, &fakeStream{ctx: incoming(md)}, &grpc.StreamServerInfo{FullMethod: tt.method}, func(srv any, ss grpc.ServerStream) error {
+				caller = Caller(ss.Context())
+				return nil
+			})
+			if status.Code(err) != tt.want || caller != tt.caller {
+				t.Errorf("stream: got caller %q with %v, want %q with %v", caller, err, tt.caller, tt.want)
+			}
+		})
+	}
+}
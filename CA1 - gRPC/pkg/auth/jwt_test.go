@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+var now = time.Unix(1_700_000_000, 0)
+
+func newTestJWT(secret string) *JWT {
+	j := NewJWT([]byte(secret))
+	j.now = func() time.Time { return now }
+	return j
+}
+
+// signRaw signs arbitrary header and payload JSON, as a forger with the
+// secret would.
+func signRaw(j *JWT, header, payload string) string {
+	enc := base64.RawURLEncoding
+	signingInput := enc.EncodeToString([]byte(header)) + "." + enc.EncodeToString([]byte(payload))
+	return signingInput + "." + enc.EncodeToString(j.sign(signingInput))
+}
+
+func mustSign(t *testing.T, j *JWT, claims Claims) string {
+	t.Helper()
+	token, err := j.Sign(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestJWTVerify(t *testing.T) {
+	j := newTestJWT("secret")
+	other := newTestJWT("other secret")
+	enc := base64.RawURLEncoding
+	valid := mustSign(t, j, Claims{Subject: "alice"})
+	unsigned := enc.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`)) + "." +
+		enc.EncodeToString([]byte(`{"sub":"alice"}`)) + "."
+
+	tests := []struct {
+		name    string
+		token   string
+		wantSub string
+	}{
+		{"valid", valid, "alice"},
+		{"valid window", mustSign(t, j, Claims{Subject: "alice", NotBefore: now.Unix(), ExpiresAt: now.Unix() + 1}), "alice"},
+		{"expired", mustSign(t, j, Claims{Subject: "alice", ExpiresAt: now.Unix()}), ""},
+		{"long expired", mustSign(t, j, Claims{Subject: "alice", ExpiresAt: now.Add(-time.Hour).Unix()}), ""},
+		{"not valid yet", mustSign(t, j, Claims{Subject: "alice", NotBefore: now.Unix() + 1}), ""},
+		{"missing subject", mustSign(t, j, Claims{Issuer: "dist-grpc"}), ""},
+		{"other secret", mustSign(t, other, Claims{Subject: "alice"}), ""},
+		{"bad signature", valid[:len(valid)-2] + "AA", ""},
+		{"undecodable signature", valid[:len(valid)-1] + "*", ""},
+		{"alg none", unsigned, ""},
+		{"alg none signed", signRaw(j, `{"alg":"none","typ":"JWT"}`, `{"sub":"alice"}`), ""},
+		{"alg HS512", signRaw(j, `{"alg":"HS512","typ":"JWT"}`, `{"sub":"alice"}`), ""},
+		{"alg lower case", signRaw(j, `{"alg":"hs256","typ":"JWT"}`, `{"sub":"alice"}`), ""},
+		{"header not JSON", signRaw(j, `alg=HS256`, `{"sub":"alice"}`), ""},
+		{"payload not JSON", signRaw(j, `{"alg":"HS256","typ":"JWT"}`, `sub=alice`), ""},
+		{"two segments", "a.b", ""},
+		{"four segments", valid + ".x", ""},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := j.Verify(tt.token)
+			if tt.wantSub == "" {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Errorf("got %+v with error %v, want %v", claims, err, ErrInvalidToken)
+				}
+				return
+			}
+			if err != nil || claims.Subject != tt.wantSub {
+				t.Errorf("got %+v with error %v, want subject %s", claims, err, tt.wantSub)
+			}
+		})
+	}
+}
+
+func TestJWTAuthenticate(t *testing.T) {
+	j := newTestJWT("secret")
+	id, err := j.Authenticate(mustSign(t, j, Claims{Subject: "alice"}))
+	if err != nil || id != (Identity{Subject: "alice", Method: "jwt"}) {
+		t.Errorf("got %v with error %v, want jwt:alice", id, err)
+	}
+}
@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+
+	"google.golang.org/grpc"
+)
+
+// PeerToken authenticates the replicas of a server, which share a token of
+// their own apart from the tokens of its clients.
+type PeerToken struct {
+	hash [sha256.Size]byte
+}
+
+func NewPeerToken(token string) *PeerToken {
+	return &PeerToken{hash: sha256.Sum256([]byte(token))}
+}
+
+func (p *PeerToken) Authenticate(token string) (Identity, error) {
+	hash := sha256.Sum256([]byte(token))
+	if subtle.ConstantTimeCompare(hash[:], p.hash[:]) != 1 {
+		return Identity{}, ErrInvalidToken
+	}
+	return Identity{Subject: "replica", Method: "peer"}, nil
+}
+
+// UnaryPeerInterceptor rejects calls to methods starting with one of the
+// prefixes without a valid bearer token, leaving the other methods to the
+// authentication of clients.
+func UnaryPeerInterceptor(a Authenticator, prefixes ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !isExempt(info.FullMethod, prefixes) {
+			return handler(ctx, req)
+		}
+		ctx, err := authenticate(ctx, a)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func StreamPeerInterceptor(a Authenticator, prefixes ...string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !isExempt(info.FullMethod, prefixes) {
+			return handler(srv, ss)
+		}
+		ctx, err := authenticate(ss.Context(), a)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
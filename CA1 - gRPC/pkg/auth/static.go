@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// StaticTokens authenticates against a fixed set of bearer tokens. Only
+// token hashes are kept in memory.
+type StaticTokens struct {
+	subjects map[[sha256.Size]byte]string
+}
+
+// LoadStaticTokens reads a file of "<token> <subject>" lines. Blank lines and
+// lines starting with # are ignored.
+func LoadStaticTokens(path string) (*StaticTokens, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open token file: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	st := &StaticTokens{subjects: make(map[[sha256.Size]byte]string)}
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: expected \"<token> <subject>\"", path, line)
+		}
+		st.subjects[sha256.Sum256([]byte(fields[0]))] = fields[1]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read token file: %w", err)
+	}
+	return st, nil
+}
+
+func (st *StaticTokens) Authenticate(token string) (Identity, error) {
+	subject, ok := st.subjects[sha256.Sum256([]byte(token))]
+	if !ok {
+		return Identity{}, ErrInvalidToken
+	}
+	return Identity{Subject: subject, Method: "token"}, nil
+}
@@ -0,0 +1,125 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound = errors.New("order not found")
+	ErrExists   = errors.New("order already exists")
+	// ErrNotLeader is returned by the writes of a replica that leaves them
+	// to the leader of a replicated catalog.
+	ErrNotLeader = errors.New("not the leader")
+	// ErrNotReplicated is returned by a write the leader applied but could
+	// not replicate to enough followers in time.
+	ErrNotReplicated = errors.New("write not replicated")
+)
+
+type Status string
+
+const (
+	StatusPending    Status = "pending"
+	StatusProcessing Status = "processing"
+	StatusShipped    Status = "shipped"
+	StatusDelivered  Status = "delivered"
+	StatusCancelled  Status = "cancelled"
+)
+
+type Order struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	Quantity int       `json:"quantity,omitempty"`
+	Price    float64   `json:"price,omitempty"`
+	Status   Status    `json:"status,omitempty"`
+	Created  time.Time `json:"created,omitempty"`
+}
+
+type ChangeKind int
+
+const (
+	Added ChangeKind = iota
+	Updated
+	Removed
+)
+
+type Change struct {
+	Kind ChangeKind
+	Old  Order
+	New  Order
+}
+
+type Store interface {
+	List() []Order
+	Get(id string) (Order, error)
+	Add(order Order) error
+	Update(order Order) error
+	Delete(id string) error
+	// Subscribe first replays every present order to fn as an Added change and
+	// then calls it after every change, in the order the changes were applied.
+	Subscribe(fn func(Change)) (unsubscribe func())
+}
+
+// Barrier is implemented by replicated stores whose copy of the catalog may
+// lag behind. Barrier returns once the copy holds every write committed
+// before the call, so that the reads following it are linearizable.
+type Barrier interface {
+	Barrier(ctx context.Context) error
+}
+
+var DefaultOrders = []Order{
+	{ID: "1", Name: "banana"},
+	{ID: "2", Name: "apple"},
+	{ID: "3", Name: "orange"},
+	{ID: "4", Name: "grape"},
+	{ID: "5", Name: "red apple"},
+	{ID: "6", Name: "kiwi"},
+	{ID: "7", Name: "mango"},
+	{ID: "8", Name: "pear"},
+	{ID: "9", Name: "cherry"},
+	{ID: "10", Name: "green apple"},
+}
+
+// Open returns the store backing the given catalog path. An empty path yields
+// an in-memory store seeded with DefaultOrders, a *.json path a FileStore.
+func Open(path string) (Store, error) {
+	if path == "" {
+		return NewMemoryStore(DefaultOrders), nil
+	}
+	if strings.HasSuffix(path, ".json") {
+		return NewFileStore(path)
+	}
+	return nil, errors.New("unsupported catalog format: " + path)
+}
+
+// Replace makes the store hold exactly the orders, only changing those that
+// differ so that subscribers only see actual changes.
+func Replace(store Store, orders []Order) error {
+	want := make(map[string]Order, len(orders))
+	for _, order := range orders {
+		want[order.ID] = order
+	}
+	for _, order := range store.List() {
+		if _, ok := want[order.ID]; !ok {
+			if err := store.Delete(order.ID); err != nil {
+				return err
+			}
+		}
+	}
+	for _, order := range orders {
+		old, err := store.Get(order.ID)
+		switch {
+		case err != nil:
+			err = store.Add(order)
+		case !old.Created.Equal(order.Created) || old.Name != order.Name || old.Quantity != order.Quantity ||
+			old.Price != order.Price || old.Status != order.Status:
+			err = store.Update(order)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package catalog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// FileStore keeps the catalog in memory and rewrites the whole JSON file on
+// every change, which is fine for catalogs that are read far more than written.
+type FileStore struct {
+	*MemoryStore
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileStore(path string) (*FileStore, error) {
+	var orders []Order
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read catalog: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &orders); err != nil {
+			return nil, fmt.Errorf("failed to parse catalog %s: %w", path, err)
+		}
+	}
+	return &FileStore{
+		MemoryStore: NewMemoryStore(orders),
+		path:        path,
+	}, nil
+}
+
+func (s *FileStore) Add(order Order) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.Get(order.ID); err == nil {
+		return ErrExists
+	}
+	if err := s.save(append(s.List(), order)); err != nil {
+		return err
+	}
+	return s.MemoryStore.Add(order)
+}
+
+func (s *FileStore) Update(order Order) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	orders := s.List()
+	i := slices.IndexFunc(orders, func(o Order) bool { return o.ID == order.ID })
+	if i < 0 {
+		return ErrNotFound
+	}
+	orders[i] = order
+	if err := s.save(orders); err != nil {
+		return err
+	}
+	return s.MemoryStore.Update(order)
+}
+
+func (s *FileStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	orders := s.List()
+	i := slices.IndexFunc(orders, func(o Order) bool { return o.ID == id })
+	if i < 0 {
+		return ErrNotFound
+	}
+	if err := s.save(slices.Delete(orders, i, i+1)); err != nil {
+		return err
+	}
+	return s.MemoryStore.Delete(id)
+}
+
+// save writes the orders the catalog is about to hold, before the change is
+// made in memory, so that a failed save leaves both as they were.
+func (s *FileStore) save(orders []Order) error {
+	data, err := json.MarshalIndent(orders, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to save catalog: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save catalog: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save catalog: %w", err)
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
@@ -0,0 +1,71 @@
+package catalog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileStoreSaves(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "catalog.json")
+	store, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Add(Order{ID: "1", Name: "apple"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Add(Order{ID: "2", Name: "banana"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Update(Order{ID: "1", Name: "red apple"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete("2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete("2"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("deleting a missing order returned %v, want %v", err, ErrNotFound)
+	}
+
+	reopened, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if orders := reopened.List(); len(orders) != 1 || orders[0].Name != "red apple" {
+		t.Fatalf("file holds %v, want red apple only", orders)
+	}
+}
+
+// TestFileStoreFailedSave checks that a write the file could not take is not
+// made in memory either.
+func TestFileStoreFailedSave(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "catalog")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	store, err := NewFileStore(filepath.Join(dir, "catalog.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Add(Order{ID: "1", Name: "apple"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Add(Order{ID: "2", Name: "banana"}); err == nil {
+		t.Fatal("add succeeded without a catalog directory")
+	}
+	if err := store.Update(Order{ID: "1", Name: "red apple"}); err == nil {
+		t.Fatal("update succeeded without a catalog directory")
+	}
+	if err := store.Delete("1"); err == nil {
+		t.Fatal("delete succeeded without a catalog directory")
+	}
+	if orders := store.List(); len(orders) != 1 || orders[0].Name != "apple" {
+		t.Fatalf("store holds %v after failed saves, want apple only", orders)
+	}
+}
@@ -0,0 +1,124 @@
+package catalog
+
+import (
+	"sync"
+)
+
+type MemoryStore struct {
+	orders      map[string]Order
+	ids         []string
+	subscribers map[int]func(Change)
+	nextSub     int
+	mu          sync.RWMutex
+	// muWrite serializes writes together with their notifications so that
+	// subscribers observe changes in order and may read the store meanwhile.
+	muWrite sync.Mutex
+}
+
+func NewMemoryStore(orders []Order) *MemoryStore {
+	s := &MemoryStore{
+		orders:      make(map[string]Order),
+		subscribers: make(map[int]func(Change)),
+	}
+	for _, order := range orders {
+		if _, ok := s.orders[order.ID]; ok {
+			continue
+		}
+		s.orders[order.ID] = order
+		s.ids = append(s.ids, order.ID)
+	}
+	return s
+}
+
+func (s *MemoryStore) List() []Order {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	orders := make([]Order, 0, len(s.ids))
+	for _, id := range s.ids {
+		orders = append(orders, s.orders[id])
+	}
+	return orders
+}
+
+func (s *MemoryStore) Get(id string) (Order, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	order, ok := s.orders[id]
+	if !ok {
+		return Order{}, ErrNotFound
+	}
+	return order, nil
+}
+
+func (s *MemoryStore) Add(order Order) error {
+	s.muWrite.Lock()
+	defer s.muWrite.Unlock()
+	s.mu.Lock()
+	if _, ok := s.orders[order.ID]; ok {
+		s.mu.Unlock()
+		return ErrExists
+	}
+	s.orders[order.ID] = order
+	s.ids = append(s.ids, order.ID)
+	s.mu.Unlock()
+	s.notify(Change{Kind: Added, New: order})
+	return nil
+}
+
+func (s *MemoryStore) Update(order Order) error {
+	s.muWrite.Lock()
+	defer s.muWrite.Unlock()
+	s.mu.Lock()
+	old, ok := s.orders[order.ID]
+	if !ok {
+		s.mu.Unlock()
+		return ErrNotFound
+	}
+	s.orders[order.ID] = order
+	s.mu.Unlock()
+	s.notify(Change{Kind: Updated, Old: old, New: order})
+	return nil
+}
+
+func (s *MemoryStore) Delete(id string) error {
+	s.muWrite.Lock()
+	defer s.muWrite.Unlock()
+	s.mu.Lock()
+	old, ok := s.orders[id]
+	if !ok {
+		s.mu.Unlock()
+		return ErrNotFound
+	}
+	delete(s.orders, id)
+	for i, v := range s.ids {
+		if v == id {
+			s.ids = append(s.ids[:i], s.ids[i+1:]...)
+			break
+		}
+	}
+	s.mu.Unlock()
+	s.notify(Change{Kind: Removed, Old: old})
+	return nil
+}
+
+func (s *MemoryStore) Subscribe(fn func(Change)) func() {
+	s.muWrite.Lock()
+	defer s.muWrite.Unlock()
+	for _, order := range s.List() {
+		fn(Change{Kind: Added, New: order})
+	}
+	id := s.nextSub
+	s.nextSub++
+	s.subscribers[id] = fn
+	return func() {
+		s.muWrite.Lock()
+		defer s.muWrite.Unlock()
+		delete(s.subscribers, id)
+	}
+}
+
+func (s *MemoryStore) notify(change Change) {
+	for _, fn := range s.subscribers {
+		fn(change)
+	}
+}
@@ -0,0 +1,47 @@
+package catalog
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "dist-grpc/pkg/proto"
+)
+
+var statusToProto = map[Status]pb.OrderStatus{
+	StatusPending:    pb.OrderStatus_ORDER_STATUS_PENDING,
+	StatusProcessing: pb.OrderStatus_ORDER_STATUS_PROCESSING,
+	StatusShipped:    pb.OrderStatus_ORDER_STATUS_SHIPPED,
+	StatusDelivered:  pb.OrderStatus_ORDER_STATUS_DELIVERED,
+	StatusCancelled:  pb.OrderStatus_ORDER_STATUS_CANCELLED,
+}
+
+func ToProto(order Order) *pb.Order {
+	res := &pb.Order{
+		Id:       order.ID,
+		Name:     order.Name,
+		Quantity: int32(order.Quantity),
+		Price:    order.Price,
+		Status:   statusToProto[order.Status],
+	}
+	if !order.Created.IsZero() {
+		res.Created = timestamppb.New(order.Created)
+	}
+	return res
+}
+
+func FromProto(order *pb.Order) Order {
+	res := Order{
+		ID:       order.GetId(),
+		Name:     order.GetName(),
+		Quantity: int(order.GetQuantity()),
+		Price:    order.GetPrice(),
+	}
+	for k, v := range statusToProto {
+		if v == order.GetStatus() {
+			res.Status = k
+		}
+	}
+	if order.GetCreated() != nil {
+		res.Created = order.GetCreated().AsTime()
+	}
+	return res
+}
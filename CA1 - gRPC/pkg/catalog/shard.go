@@ -0,0 +1,66 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// ShardOf is the shard, out of count, that holds the order with the id.
+func ShardOf(id string, count int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(id))
+	return int(h.Sum32() % uint32(count))
+}
+
+// Partition returns the orders that belong to shard index out of count. The
+// orders are filtered rather than deleted from their store, which for a
+// file would erase the orders of the other shards.
+func Partition(orders []Order, index, count int) []Order {
+	var kept []Order
+	for _, order := range orders {
+		if ShardOf(order.ID, count) == index {
+			kept = append(kept, order)
+		}
+	}
+	return kept
+}
+
+// ShardPath is the file that shard index out of count of the catalog file
+// at path keeps its orders in, "orders.json" giving "orders.shard-1-of-3.json".
+func ShardPath(path string, index, count int) string {
+	return fmt.Sprintf("%s.shard-%d-of-%d.json", strings.TrimSuffix(path, ".json"), index, count)
+}
+
+// OpenShard returns the store of shard index out of count of the catalog at
+// path. An empty path yields the shard's part of DefaultOrders in memory. A
+// file catalog is left to the other shards: the shard keeps its orders and
+// writes in its own file, seeded with its part of the catalog the first
+// time it is opened.
+func OpenShard(path string, index, count int) (Store, error) {
+	if path == "" {
+		return NewMemoryStore(Partition(DefaultOrders, index, count)), nil
+	}
+	shardPath := ShardPath(path, index, count)
+	if _, err := os.Stat(shardPath); err == nil {
+		return NewFileStore(shardPath)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to open shard: %w", err)
+	}
+	whole, err := Open(path)
+	if err != nil {
+		return nil, err
+	}
+	shard, err := NewFileStore(shardPath)
+	if err != nil {
+		return nil, err
+	}
+	orders := append([]Order{}, Partition(whole.List(), index, count)...)
+	if err := shard.save(orders); err != nil {
+		return nil, err
+	}
+	return NewFileStore(shardPath)
+}
@@ -0,0 +1,274 @@
+// Package gateway exposes the OrderManagement queries over HTTP/JSON by
+// calling the gRPC service, in the manner of gRPC-Gateway:
+//
+//	GET  /v1/orders?query=...          GetOrderUnary, one page as JSON
+//	GET  /v1/orders:stream?query=...   GetOrderServerStream as NDJSON or SSE
+//	POST /v1/orders:aggregate          GetOrderClientStream over a JSON array
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	pb "dist-grpc/pkg/proto"
+)
+
+const maxBodySize = 1 << 20
+
+type Gateway struct {
+	client pb.OrderManagementClient
+	mux    *http.ServeMux
+}
+
+func New(client pb.OrderManagementClient) *Gateway {
+	g := &Gateway{client: client, mux: http.NewServeMux()}
+	g.mux.HandleFunc("GET /v1/orders", g.unary)
+	g.mux.HandleFunc("GET /v1/orders:stream", g.serverStream)
+	g.mux.HandleFunc("POST /v1/orders:aggregate", g.clientStream)
+	return g
+}
+
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mux.ServeHTTP(w, r)
+}
+
+// outgoing forwards the bearer token of the HTTP request so that the gRPC
+// service authenticates the caller as it would a gRPC client.
+func outgoing(r *http.Request) *http.Request {
+	if token := r.Header.Get("Authorization"); token != "" {
+		return r.WithContext(metadata.AppendToOutgoingContext(r.Context(), "authorization", token))
+	}
+	return r
+}
+
+// enumValue reads an enum parameter either by its full name or by the part
+// after the prefix, case-insensitively: fuzzy, FUZZY and MATCH_MODE_FUZZY.
+func enumValue(values map[string]int32, prefix, name string) (int32, error) {
+	upper := strings.ToUpper(name)
+	if v, ok := values[upper]; ok {
+		return v, nil
+	}
+	if v, ok := values[prefix+upper]; ok {
+		return v, nil
+	}
+	return 0, fmt.Errorf("unknown value %q", name)
+}
+
+// parseRequest builds a request from the URL parameters, which are named
+// after the fields of Request.
+func parseRequest(params url.Values) (*pb.Request, error) {
+	req := &pb.Request{
+		Query:     params.Get("query"),
+		PageToken: params.Get("page_token"),
+		RequestId: params.Get("request_id"),
+	}
+	var violations []string
+	for _, name := range []string{"page_size", "max_results"} {
+		if params.Get(name) == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(params.Get(name), 10, 32)
+		if err != nil || n < 0 {
+			violations = append(violations, fmt.Sprintf("%s must be a non-negative integer", name))
+			continue
+		}
+		if name == "page_size" {
+			req.PageSize = int32(n)
+		} else {
+			req.MaxResults = int32(n)
+		}
+	}
+	if mode := params.Get("mode"); mode != "" {
+		v, err := enumValue(pb.MatchMode_value, "MATCH_MODE_", mode)
+		if err != nil {
+			violations = append(violations, "mode: "+err.Error())
+		}
+		req.Mode = pb.MatchMode(v)
+	}
+	if aggregation := params.Get("aggregation"); aggregation != "" {
+		v, err := enumValue(pb.Aggregation_value, "AGGREGATION_", aggregation)
+		if err != nil {
+			violations = append(violations, "aggregation: "+err.Error())
+		}
+		req.Aggregation = pb.Aggregation(v)
+	}
+	if consistency := params.Get("consistency"); consistency != "" {
+		v, err := enumValue(pb.ReadConsistency_value, "READ_CONSISTENCY_", consistency)
+		if err != nil {
+			violations = append(violations, "consistency: "+err.Error())
+		}
+		req.Consistency = pb.ReadConsistency(v)
+	}
+	if len(violations) > 0 {
+		return nil, status.Error(codes.InvalidArgument, strings.Join(violations, "; "))
+	}
+	return req, nil
+}
+
+func (g *Gateway) unary(w http.ResponseWriter, r *http.Request) {
+	req, err := parseRequest(r.URL.Query())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	res, err := g.client.GetOrderUnary(outgoing(r).Context(), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, http.StatusOK, res)
+}
+
+// serverStream sends every response as soon as it is received, as
+// Server-Sent Events when the caller accepts text/event-stream or asks for
+// format=sse and as newline-delimited JSON otherwise.
+func (g *Gateway) serverStream(w http.ResponseWriter, r *http.Request) {
+	req, err := parseRequest(r.URL.Query())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var out streamWriter = &ndjsonWriter{w: w}
+	switch format := r.URL.Query().Get("format"); {
+	case format == "sse", format == "" && strings.Contains(r.Header.Get("Accept"), "text/event-stream"):
+		out = &sseWriter{w: w}
+	case format != "" && format != "ndjson":
+		writeError(w, status.Errorf(codes.InvalidArgument, "format must be ndjson or sse, got %q", format))
+		return
+	}
+
+	stream, err := g.client.GetOrderServerStream(outgoing(r).Context(), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	// The status of a stream is only known once its first message or error
+	// arrives, so the headers wait for it.
+	res, err := stream.Recv()
+	if err != nil && err != io.EOF {
+		writeError(w, err)
+		return
+	}
+	out.begin()
+	for err == nil {
+		if err = out.result(res); err != nil {
+			log.Debug("Gateway client went away", "err", err)
+			return
+		}
+		res, err = stream.Recv()
+	}
+	if err != io.EOF {
+		_ = out.error(status.Convert(err))
+	}
+}
+
+// clientStream streams the elements of a JSON array to the service and
+// returns its single response. An element is either a query string, sent
+// with the URL parameters as its other fields, or a Request object.
+func (g *Gateway) clientStream(w http.ResponseWriter, r *http.Request) {
+	template, err := parseRequest(r.URL.Query())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var elements []json.RawMessage
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(&elements); err != nil {
+		writeError(w, status.Errorf(codes.InvalidArgument, "body must be a JSON array of queries or requests: %v", err))
+		return
+	}
+	requests := make([]*pb.Request, len(elements))
+	for i, element := range elements {
+		var query string
+		if json.Unmarshal(element, &query) == nil {
+			req := proto.Clone(template).(*pb.Request)
+			req.Query = query
+			requests[i] = req
+			continue
+		}
+		req := &pb.Request{}
+		if err := protojson.Unmarshal(element, req); err != nil {
+			writeError(w, status.Errorf(codes.InvalidArgument, "element %d is neither a query nor a request: %v", i, err))
+			return
+		}
+		requests[i] = req
+	}
+
+	stream, err := g.client.GetOrderClientStream(outgoing(r).Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	for _, req := range requests {
+		// A failed send is reported by CloseAndRecv.
+		if stream.Send(req) != nil {
+			break
+		}
+	}
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, http.StatusOK, res)
+}
+
+func writeMessage(w http.ResponseWriter, code int, m proto.Message) {
+	data, err := protojson.Marshal(m)
+	if err != nil {
+		log.Error("Failed to marshal response", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, _ = w.Write(append(data, '\n'))
+}
+
+// writeError answers with the HTTP status of the gRPC code and the
+// google.rpc.Status of the error, details included, as the body.
+func writeError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+	writeMessage(w, HTTPStatus(st.Code()), st.Proto())
+}
+
+// HTTPStatus maps a gRPC code to the HTTP status gRPC-Gateway uses for it.
+func HTTPStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
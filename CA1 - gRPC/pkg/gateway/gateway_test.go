@@ -0,0 +1,195 @@
+package gateway_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"dist-grpc/internal/harnesstest"
+	"dist-grpc/pkg/catalog"
+	"dist-grpc/pkg/gateway"
+	"dist-grpc/pkg/harness"
+	"dist-grpc/pkg/matcher"
+	pb "dist-grpc/pkg/proto"
+	"dist-grpc/pkg/server"
+	"dist-grpc/pkg/watch"
+)
+
+// startGateway serves the gateway over HTTP in front of a harness on the
+// default catalog.
+func startGateway(t *testing.T) *httptest.Server {
+	t.Helper()
+	store := catalog.NewMemoryStore(catalog.DefaultOrders)
+	h, err := harness.Start(store, matcher.New(store), server.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(h.Close)
+	srv := httptest.NewServer(gateway.New(h.Client))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// fetch answers a request with its status code and body, failing when the
+// content type is not the expected one.
+func fetch(t *testing.T, req *http.Request, contentType string) (int, []byte) {
+	t.Helper()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.Header.Get("Content-Type"); got != contentType {
+		t.Fatalf("got content type %q, want %q", got, contentType)
+	}
+	return resp.StatusCode, body
+}
+
+// fetchOK is fetch for a request that must succeed.
+func fetchOK(t *testing.T, req *http.Request, contentType string) []byte {
+	t.Helper()
+	code, body := fetch(t, req, contentType)
+	if code != http.StatusOK {
+		t.Fatalf("got HTTP %d: %s", code, body)
+	}
+	return body
+}
+
+func TestGatewayUnary(t *testing.T) {
+	srv := startGateway(t)
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/v1/orders?query=apple&page_size=2&mode=substring", nil)
+	res := &pb.Response{}
+	if err := protojson.Unmarshal(fetchOK(t, req, "application/json"), res); err != nil {
+		t.Fatal(err)
+	}
+	if res.GetNextPageToken() == "" || res.GetTotalCount() != 3 {
+		t.Fatalf("got %v, want the first of two pages", res)
+	}
+	harnesstest.ExpectResults(t, res.GetResults(), "apple", "red apple")
+}
+
+func TestGatewayInvalidQuery(t *testing.T) {
+	srv := startGateway(t)
+
+	for _, path := range []string{"/v1/orders?query=bad!", "/v1/orders:stream?query=bad!", "/v1/orders?query=apple&mode=loose"} {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+		code, body := fetch(t, req, "application/json")
+		var st struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(body, &st); err != nil {
+			t.Fatal(err)
+		}
+		if code != http.StatusBadRequest || st.Code != int(codes.InvalidArgument) || st.Message == "" {
+			t.Fatalf("%s: got HTTP %d with %s, want 400 with an InvalidArgument status", path, code, body)
+		}
+	}
+}
+
+func TestGatewayNDJSON(t *testing.T) {
+	srv := startGateway(t)
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/v1/orders:stream?query=apple", nil)
+	body := fetchOK(t, req, "application/x-ndjson")
+	var results []string
+	scanner := bufio.NewScanner(strings.NewReader(string(body)))
+	for scanner.Scan() {
+		var line struct {
+			Result json.RawMessage `json:"result"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatal(err)
+		}
+		res := &pb.Response{}
+		if err := protojson.Unmarshal(line.Result, res); err != nil {
+			t.Fatalf("line %q: %v", scanner.Text(), err)
+		}
+		results = append(results, res.GetResults()...)
+	}
+	harnesstest.ExpectResults(t, results, "apple", "red apple", "green apple")
+}
+
+func TestGatewaySSE(t *testing.T) {
+	srv := startGateway(t)
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/v1/orders:stream?query=apple", nil)
+	req.Header.Set("Accept", "text/event-stream")
+	body := fetchOK(t, req, "text/event-stream")
+	var results []string
+	for _, event := range strings.Split(strings.TrimSpace(string(body)), "\n\n") {
+		name, data, ok := strings.Cut(event, "\n")
+		if !ok || name != "event: message" || !strings.HasPrefix(data, "data: ") {
+			t.Fatalf("malformed event %q", event)
+		}
+		res := &pb.Response{}
+		if err := protojson.Unmarshal([]byte(strings.TrimPrefix(data, "data: ")), res); err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, res.GetResults()...)
+	}
+	harnesstest.ExpectResults(t, results, "apple", "red apple", "green apple")
+}
+
+// TestGatewayAggregate mixes plain queries, which take the URL parameters,
+// with a request object.
+func TestGatewayAggregate(t *testing.T) {
+	srv := startGateway(t)
+
+	body := `["apple", "red", {"query": "banana", "mode": "MATCH_MODE_EXACT"}]`
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/v1/orders:aggregate?aggregation=counts", strings.NewReader(body))
+	res := &pb.Response{}
+	if err := protojson.Unmarshal(fetchOK(t, req, "application/json"), res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.GetCounts()) != 4 || res.GetCounts()[0].GetResult() != "red apple" || res.GetCounts()[0].GetCount() != 2 {
+		t.Fatalf("got counts %v, want red apple matched twice first among 4", res.GetCounts())
+	}
+
+	req, _ = http.NewRequest(http.MethodPost, srv.URL+"/v1/orders:aggregate", strings.NewReader(`{"query": "apple"}`))
+	if code, data := fetch(t, req, "application/json"); code != http.StatusBadRequest {
+		t.Fatalf("got HTTP %d for a body that is not an array: %s", code, data)
+	}
+}
+
+// TestLoopback checks that the gateway reaches the server in-process, and
+// that calls fail once the server is stopped.
+func TestLoopback(t *testing.T) {
+	store := catalog.NewMemoryStore(catalog.DefaultOrders)
+	m := matcher.New(store)
+	defer m.Close()
+	hub := watch.NewHub(store, watch.DefaultBuffer)
+	defer hub.Close()
+	grpcServer := grpc.NewServer()
+	pb.RegisterOrderManagementServer(grpcServer, server.New(store, m, hub, server.Config{}))
+	conn, err := gateway.Loopback(grpcServer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	client := pb.NewOrderManagementClient(conn)
+	res, err := client.GetOrderUnary(context.Background(), &pb.Request{Query: "apple"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	harnesstest.ExpectResults(t, res.GetResults(), "apple", "red apple", "green apple")
+
+	grpcServer.Stop()
+	_, err = client.GetOrderUnary(context.Background(), &pb.Request{Query: "apple"})
+	harnesstest.ExpectCode(t, err, codes.Unavailable)
+}
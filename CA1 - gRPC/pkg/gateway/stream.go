@@ -0,0 +1,85 @@
+package gateway
+
+import (
+	"fmt"
+	"net/http"
+
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	pb "dist-grpc/pkg/proto"
+)
+
+// streamWriter writes the responses of a server stream, flushing each one,
+// and the error that ended it, if any.
+type streamWriter interface {
+	begin()
+	result(res *pb.Response) error
+	error(st *status.Status) error
+}
+
+func flush(w http.ResponseWriter) error {
+	return http.NewResponseController(w).Flush()
+}
+
+// ndjsonWriter writes a line per message, {"result": ...} or {"error": ...}
+// like gRPC-Gateway does.
+type ndjsonWriter struct {
+	w http.ResponseWriter
+}
+
+func (n *ndjsonWriter) begin() {
+	n.w.Header().Set("Content-Type", "application/x-ndjson")
+	n.w.WriteHeader(http.StatusOK)
+}
+
+func (n *ndjsonWriter) line(key string, m proto.Message) error {
+	data, err := protojson.Marshal(m)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(n.w, "{%q:%s}\n", key, data); err != nil {
+		return err
+	}
+	return flush(n.w)
+}
+
+func (n *ndjsonWriter) result(res *pb.Response) error {
+	return n.line("result", res)
+}
+
+func (n *ndjsonWriter) error(st *status.Status) error {
+	return n.line("error", st.Proto())
+}
+
+// sseWriter writes a message event per response and an error event holding
+// the status that ended the stream.
+type sseWriter struct {
+	w http.ResponseWriter
+}
+
+func (s *sseWriter) begin() {
+	s.w.Header().Set("Content-Type", "text/event-stream")
+	s.w.Header().Set("Cache-Control", "no-cache")
+	s.w.WriteHeader(http.StatusOK)
+}
+
+func (s *sseWriter) event(name string, m proto.Message) error {
+	data, err := protojson.Marshal(m)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", name, data); err != nil {
+		return err
+	}
+	return flush(s.w)
+}
+
+func (s *sseWriter) result(res *pb.Response) error {
+	return s.event("message", res)
+}
+
+func (s *sseWriter) error(st *status.Status) error {
+	return s.event("error", st.Proto())
+}
@@ -0,0 +1,275 @@
+package harness
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"slices"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"dist-grpc/pkg/catalog"
+	pb "dist-grpc/pkg/proto"
+	"dist-grpc/pkg/raft"
+	"dist-grpc/pkg/server"
+)
+
+// Cluster runs the members of a Raft cluster in-process, each on a harness
+// of its own, over a network that can be partitioned and can drop messages.
+// Members are addressed by their id.
+type Cluster struct {
+	config raft.Config
+
+	mu      sync.Mutex
+	members map[string]*Member
+	// group is the side of the partition each member is on, all of them
+	// reaching each other while it is empty.
+	group    map[string]int
+	dropRate float64
+	rand     *rand.Rand
+}
+
+// Member is a node of the cluster with the harness serving it. Its storage
+// outlives the node, so that the member can be restarted from it.
+type Member struct {
+	ID      string
+	Node    *raft.Node
+	Harness *Harness
+	storage raft.Storage
+}
+
+// StartCluster starts a member per id from the same catalog, which newStore
+// returns a copy of. The config sets the timings of every member.
+func StartCluster(ids []string, newStore func() catalog.Store, config raft.Config) (*Cluster, error) {
+	c := &Cluster{
+		config:  config,
+		members: make(map[string]*Member),
+		group:   make(map[string]int),
+		rand:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	var members []*pb.Member
+	for _, id := range ids {
+		members = append(members, &pb.Member{Id: id, Address: id})
+	}
+	for _, id := range ids {
+		if _, err := c.start(id, newStore(), members, raft.NewMemoryStorage()); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (c *Cluster) start(id string, store catalog.Store, members []*pb.Member, storage raft.Storage) (*Member, error) {
+	config := c.config
+	config.ID, config.Storage, config.Dial = id, storage, c.dialer(id)
+	node, err := raft.New(store, members, config)
+	if err != nil {
+		return nil, err
+	}
+	h, err := StartRaft(node, server.Config{})
+	if err != nil {
+		node.Close()
+		return nil, err
+	}
+	m := &Member{ID: id, Node: node, Harness: h, storage: storage}
+	c.mu.Lock()
+	c.members[id] = m
+	c.mu.Unlock()
+	return m, nil
+}
+
+// Join starts a member with an empty catalog, outside of the cluster until
+// its leader adds it.
+func (c *Cluster) Join(id string) (*Member, error) {
+	return c.start(id, catalog.NewMemoryStore(nil), nil, raft.NewMemoryStorage())
+}
+
+// Stop stops the member as if it crashed, keeping its storage.
+func (c *Cluster) Stop(id string) {
+	c.mu.Lock()
+	m := c.members[id]
+	delete(c.members, id)
+	c.mu.Unlock()
+	if m != nil {
+		m.Node.Close()
+		m.Harness.Close()
+	}
+}
+
+// Restart starts a stopped member again from its storage, with an empty
+// catalog its snapshot and log restore.
+func (c *Cluster) Restart(m *Member) (*Member, error) {
+	return c.start(m.ID, catalog.NewMemoryStore(nil), nil, m.storage)
+}
+
+func (c *Cluster) Close() {
+	c.mu.Lock()
+	members := c.members
+	c.members = make(map[string]*Member)
+	c.mu.Unlock()
+	for _, m := range members {
+		m.Node.Close()
+		m.Harness.Close()
+	}
+}
+
+// Member returns the running member of the id, or nil.
+func (c *Cluster) Member(id string) *Member {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.members[id]
+}
+
+// Partition splits the network so that members only reach those of their
+// group. Members left out of every group form one more group.
+func (c *Cluster) Partition(groups ...[]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.group = make(map[string]int)
+	for i, ids := range groups {
+		for _, id := range ids {
+			c.group[id] = i + 1
+		}
+	}
+}
+
+// Heal lets every member reach every other again.
+func (c *Cluster) Heal() {
+	c.Partition()
+}
+
+// Drop makes the network lose each request, and each response, with the
+// probability rate.
+func (c *Cluster) Drop(rate float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.dropRate = rate
+}
+
+// Leader waits for one of the members to lead, the one of the latest term
+// when a partitioned leader still believes it leads. ids limits the wait to
+// those members.
+func (c *Cluster) Leader(timeout time.Duration, ids ...string) (*Member, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		var leader *Member
+		var term uint64
+		c.mu.Lock()
+		for id, m := range c.members {
+			if len(ids) > 0 && !slices.Contains(ids, id) {
+				continue
+			}
+			st, _ := m.Node.Status(context.Background(), &pb.RaftStatusRequest{})
+			if st.GetState() == string(raft.Leader) && st.GetTerm() > term {
+				leader, term = m, st.GetTerm()
+			}
+		}
+		c.mu.Unlock()
+		if leader != nil {
+			return leader, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("no leader elected within %s", timeout)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func (c *Cluster) dialer(from string) func(addr string) (pb.RaftClient, error) {
+	return func(addr string) (pb.RaftClient, error) {
+		return &raftLink{c: c, from: from, to: addr}, nil
+	}
+}
+
+// reach returns the client of the member, unless the network keeps it from
+// the caller or loses the message.
+func (c *Cluster) reach(from, to string) (pb.RaftClient, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	m := c.members[to]
+	switch {
+	case m == nil:
+		return nil, status.Errorf(codes.Unavailable, "%s is down", to)
+	case c.group[from] != c.group[to]:
+		return nil, status.Errorf(codes.Unavailable, "%s cannot reach %s", from, to)
+	case c.dropped():
+		return nil, status.Errorf(codes.Unavailable, "message from %s to %s dropped", from, to)
+	}
+	return m.Harness.Raft, nil
+}
+
+// dropped tells whether a message is lost. It is called with mu held.
+func (c *Cluster) dropped() bool {
+	return c.dropRate > 0 && c.rand.Float64() < c.dropRate
+}
+
+func (c *Cluster) replyDropped(from, to string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.dropped() {
+		return status.Errorf(codes.Unavailable, "reply from %s to %s dropped", to, from)
+	}
+	return nil
+}
+
+// raftLink is the Raft client of a member for another, going through the
+// network of the cluster. The member it reaches is looked up on every call,
+// so that the link survives restarts.
+type raftLink struct {
+	c        *Cluster
+	from, to string
+}
+
+func send[Resp any](l *raftLink, call func(pb.RaftClient) (Resp, error)) (Resp, error) {
+	var zero Resp
+	client, err := l.c.reach(l.from, l.to)
+	if err != nil {
+		return zero, err
+	}
+	resp, err := call(client)
+	if err != nil {
+		return zero, err
+	}
+	// The request went through, and only its reply is lost.
+	if err := l.c.replyDropped(l.from, l.to); err != nil {
+		return zero, err
+	}
+	return resp, nil
+}
+
+func (l *raftLink) RequestVote(ctx context.Context, in *pb.VoteRequest, opts ...grpc.CallOption) (*pb.VoteResponse, error) {
+	return send(l, func(c pb.RaftClient) (*pb.VoteResponse, error) { return c.RequestVote(ctx, in, opts...) })
+}
+
+func (l *raftLink) AppendEntries(ctx context.Context, in *pb.AppendEntriesRequest, opts ...grpc.CallOption) (*pb.AppendEntriesResponse, error) {
+	return send(l, func(c pb.RaftClient) (*pb.AppendEntriesResponse, error) { return c.AppendEntries(ctx, in, opts...) })
+}
+
+func (l *raftLink) InstallSnapshot(ctx context.Context, in *pb.InstallSnapshotRequest, opts ...grpc.CallOption) (*pb.InstallSnapshotResponse, error) {
+	return send(l, func(c pb.RaftClient) (*pb.InstallSnapshotResponse, error) { return c.InstallSnapshot(ctx, in, opts...) })
+}
+
+func (l *raftLink) Propose(ctx context.Context, in *pb.ProposeRequest, opts ...grpc.CallOption) (*pb.ProposeResponse, error) {
+	return send(l, func(c pb.RaftClient) (*pb.ProposeResponse, error) { return c.Propose(ctx, in, opts...) })
+}
+
+func (l *raftLink) ReadIndex(ctx context.Context, in *pb.ReadIndexRequest, opts ...grpc.CallOption) (*pb.ReadIndexResponse, error) {
+	return send(l, func(c pb.RaftClient) (*pb.ReadIndexResponse, error) { return c.ReadIndex(ctx, in, opts...) })
+}
+
+func (l *raftLink) AddMember(ctx context.Context, in *pb.MembershipRequest, opts ...grpc.CallOption) (*pb.RaftStatus, error) {
+	return send(l, func(c pb.RaftClient) (*pb.RaftStatus, error) { return c.AddMember(ctx, in, opts...) })
+}
+
+func (l *raftLink) RemoveMember(ctx context.Context, in *pb.MembershipRequest, opts ...grpc.CallOption) (*pb.RaftStatus, error) {
+	return send(l, func(c pb.RaftClient) (*pb.RaftStatus, error) { return c.RemoveMember(ctx, in, opts...) })
+}
+
+func (l *raftLink) Status(ctx context.Context, in *pb.RaftStatusRequest, opts ...grpc.CallOption) (*pb.RaftStatus, error) {
+	return send(l, func(c pb.RaftClient) (*pb.RaftStatus, error) { return c.Status(ctx, in, opts...) })
+}
@@ -0,0 +1,68 @@
+package harness
+
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"dist-grpc/pkg/catalog"
+)
+
+// Fruits are the names of the fake catalog most tests serve. "apple"
+// matches, by score, AppleResults: the two in the middle are tied and kept in
+// catalog order.
+var (
+	Fruits       = []string{"apple", "apple pie", "banana", "pineapple", "cherry", "green apple"}
+	AppleResults = []string{"apple", "apple pie", "pineapple", "green apple"}
+)
+
+// FakeStore is an in-memory catalog for tests: its reads can be slowed
+// down, its writes can be made to fail and it counts the scans it serves.
+// ListDelay and WriteErr are set before the store is served.
+type FakeStore struct {
+	*catalog.MemoryStore
+	ListDelay time.Duration
+	WriteErr  error
+	lists     atomic.Int64
+}
+
+// NewFakeStore holds an order per name, with ids counting up from 1.
+func NewFakeStore(names ...string) *FakeStore {
+	orders := make([]catalog.Order, len(names))
+	for i, name := range names {
+		orders[i] = catalog.Order{ID: strconv.Itoa(i + 1), Name: name, Status: catalog.StatusPending}
+	}
+	return &FakeStore{MemoryStore: catalog.NewMemoryStore(orders)}
+}
+
+func (s *FakeStore) List() []catalog.Order {
+	s.lists.Add(1)
+	time.Sleep(s.ListDelay)
+	return s.MemoryStore.List()
+}
+
+// Lists is how many times the whole catalog was read.
+func (s *FakeStore) Lists() int {
+	return int(s.lists.Load())
+}
+
+func (s *FakeStore) Add(order catalog.Order) error {
+	if s.WriteErr != nil {
+		return s.WriteErr
+	}
+	return s.MemoryStore.Add(order)
+}
+
+func (s *FakeStore) Update(order catalog.Order) error {
+	if s.WriteErr != nil {
+		return s.WriteErr
+	}
+	return s.MemoryStore.Update(order)
+}
+
+func (s *FakeStore) Delete(id string) error {
+	if s.WriteErr != nil {
+		return s.WriteErr
+	}
+	return s.MemoryStore.Delete(id)
+}
@@ -0,0 +1,218 @@
+// Package harness runs the OrderManagement server in-process over an
+// in-memory bufconn listener, so that tests can exercise it through a
+// real gRPC client without opening a port.
+package harness
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"dist-grpc/pkg/catalog"
+	"dist-grpc/pkg/matcher"
+	pb "dist-grpc/pkg/proto"
+	"dist-grpc/pkg/raft"
+	"dist-grpc/pkg/replication"
+	"dist-grpc/pkg/server"
+	"dist-grpc/pkg/watch"
+)
+
+const bufferSize = 1 << 20
+
+// HandledTimeout bounds how long tests wait for the server to finish an RPC.
+const HandledTimeout = 5 * time.Second
+
+// Handled is the outcome of an RPC as the server handler returned it.
+type Handled struct {
+	Method   string
+	Err      error
+	Duration time.Duration
+}
+
+func (h Handled) String() string {
+	return fmt.Sprintf("%s: %s after %s", h.Method, status.Code(h.Err), h.Duration)
+}
+
+type Harness struct {
+	Store   catalog.Store
+	Matcher *matcher.Matcher
+	Hub     *watch.Hub
+	Server  *grpc.Server
+	Conn    *grpc.ClientConn
+	Client  pb.OrderManagementClient
+	// Replication is set for the harnesses of replicas.
+	Replication pb.ReplicationClient
+	// Raft is set for the harnesses of Raft members.
+	Raft pb.RaftClient
+
+	listener *bufconn.Listener
+	handled  chan Handled
+}
+
+// Start serves the store with the matcher on a bufconn listener and dials
+// it. The options are added to the server after the recording interceptors.
+func Start(store catalog.Store, m *matcher.Matcher, config server.Config, opts ...grpc.ServerOption) (*Harness, error) {
+	hub := watch.NewHub(store, watch.DefaultBuffer)
+	h, err := Serve(server.New(store, m, hub, config), opts...)
+	if err != nil {
+		hub.Close()
+		return nil, err
+	}
+	h.Store, h.Matcher, h.Hub = store, m, hub
+	return h, nil
+}
+
+// StartReplica is Start for a replica of a replicated catalog, which also
+// serves the Replication service of the node.
+func StartReplica(node *replication.Node, config server.Config, opts ...grpc.ServerOption) (*Harness, error) {
+	m := matcher.New(node)
+	hub := watch.NewHub(node, watch.DefaultBuffer)
+	h, err := serve(func(s *grpc.Server) {
+		pb.RegisterOrderManagementServer(s, server.New(node, m, hub, config))
+		pb.RegisterReplicationServer(s, node)
+	}, opts)
+	if err != nil {
+		hub.Close()
+		m.Close()
+		return nil, err
+	}
+	h.Store, h.Matcher, h.Hub = node, m, hub
+	h.Replication = pb.NewReplicationClient(h.Conn)
+	return h, nil
+}
+
+// StartRaft is Start for a member of a Raft cluster, which also serves the
+// Raft service of the node.
+func StartRaft(node *raft.Node, config server.Config, opts ...grpc.ServerOption) (*Harness, error) {
+	m := matcher.New(node)
+	hub := watch.NewHub(node, watch.DefaultBuffer)
+	h, err := serve(func(s *grpc.Server) {
+		pb.RegisterOrderManagementServer(s, server.New(node, m, hub, config))
+		pb.RegisterRaftServer(s, node)
+	}, opts)
+	if err != nil {
+		hub.Close()
+		m.Close()
+		return nil, err
+	}
+	h.Store, h.Matcher, h.Hub = node, m, hub
+	h.Raft = pb.NewRaftClient(h.Conn)
+	return h, nil
+}
+
+// Serve is Start for any implementation of the service, such as a
+// coordinator over other harnesses.
+func Serve(srv pb.OrderManagementServer, opts ...grpc.ServerOption) (*Harness, error) {
+	return serve(func(s *grpc.Server) {
+		pb.RegisterOrderManagementServer(s, srv)
+	}, opts)
+}
+
+func serve(register func(*grpc.Server), opts []grpc.ServerOption) (*Harness, error) {
+	h := &Harness{
+		listener: bufconn.Listen(bufferSize),
+		handled:  make(chan Handled, 64),
+	}
+	opts = append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(h.unaryInterceptor),
+		grpc.ChainStreamInterceptor(h.streamInterceptor),
+	}, opts...)
+	h.Server = grpc.NewServer(opts...)
+	register(h.Server)
+	go func() {
+		_ = h.Server.Serve(h.listener)
+	}()
+
+	conn, err := h.Dial()
+	if err != nil {
+		h.Server.Stop()
+		return nil, err
+	}
+	h.Conn = conn
+	h.Client = pb.NewOrderManagementClient(conn)
+	return h, nil
+}
+
+// Dial opens another connection to the server.
+func (h *Harness) Dial(opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	opts = append([]grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return h.Connect(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}, opts...)
+	return grpc.Dial("bufnet", opts...)
+}
+
+// Connect opens a raw connection to the in-memory listener, for dialers
+// spreading connections over several harnesses.
+func (h *Harness) Connect(ctx context.Context) (net.Conn, error) {
+	return h.listener.DialContext(ctx)
+}
+
+// AddOrder adds an order of the name through the server.
+func (h *Harness) AddOrder(name string) (*pb.Order, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), HandledTimeout)
+	defer cancel()
+	return h.Client.AddOrder(ctx, &pb.Order{Name: name})
+}
+
+// Catalog lists the orders of the store as sorted "id=name" pairs, which
+// compare the catalogs of replicas.
+func Catalog(store catalog.Store) []string {
+	var orders []string
+	for _, order := range store.List() {
+		orders = append(orders, order.ID+"="+order.Name)
+	}
+	slices.Sort(orders)
+	return orders
+}
+
+func (h *Harness) Close() {
+	_ = h.Conn.Close()
+	if h.Hub != nil {
+		h.Hub.Close()
+	}
+	h.Server.Stop()
+	if h.Matcher != nil {
+		h.Matcher.Close()
+	}
+}
+
+// WaitHandled returns the next RPC the server finished handling.
+func (h *Harness) WaitHandled(timeout time.Duration) (Handled, error) {
+	select {
+	case handled := <-h.handled:
+		return handled, nil
+	case <-time.After(timeout):
+		return Handled{}, fmt.Errorf("no RPC handled within %s", timeout)
+	}
+}
+
+func (h *Harness) record(method string, start time.Time, err error) {
+	select {
+	case h.handled <- Handled{Method: method, Err: err, Duration: time.Since(start)}:
+	default:
+	}
+}
+
+func (h *Harness) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	h.record(info.FullMethod, start, err)
+	return resp, err
+}
+
+func (h *Harness) streamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	h.record(info.FullMethod, start, err)
+	return err
+}
@@ -0,0 +1,65 @@
+package interceptor
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// InstanceKey is the header metadata holding the id of the server instance
+// that served an RPC, so that clients balancing over replicas can tell them
+// apart.
+const InstanceKey = "instance-id"
+
+// UnaryInstance names the instance in the header of a response but in the
+// trailer of a failure, since a response carrying headers commits the call
+// and the client would no longer retry it.
+func UnaryInstance(id string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		res, err := handler(ctx, req)
+		if err != nil {
+			_ = grpc.SetTrailer(ctx, metadata.Pairs(InstanceKey, id))
+		} else {
+			_ = grpc.SetHeader(ctx, metadata.Pairs(InstanceKey, id))
+		}
+		return res, err
+	}
+}
+
+// instanceStream sets the instance header once the handler sends something.
+type instanceStream struct {
+	grpc.ServerStream
+	id   string
+	once sync.Once
+}
+
+func (s *instanceStream) stamp() {
+	s.once.Do(func() {
+		_ = s.ServerStream.SetHeader(metadata.Pairs(InstanceKey, s.id))
+	})
+}
+
+func (s *instanceStream) SendHeader(md metadata.MD) error {
+	s.stamp()
+	return s.ServerStream.SendHeader(md)
+}
+
+func (s *instanceStream) SendMsg(m any) error {
+	s.stamp()
+	return s.ServerStream.SendMsg(m)
+}
+
+func StreamInstance(id string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		stream := &instanceStream{ServerStream: ss, id: id}
+		err := handler(srv, stream)
+		if err != nil {
+			ss.SetTrailer(metadata.Pairs(InstanceKey, id))
+		} else {
+			stream.stamp()
+		}
+		return err
+	}
+}
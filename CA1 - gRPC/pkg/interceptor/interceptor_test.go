@@ -0,0 +1,186 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"dist-grpc/pkg/metrics"
+)
+
+type fakeStream struct {
+	grpc.ServerStream
+}
+
+func (fakeStream) Context() context.Context {
+	return context.Background()
+}
+
+func (fakeStream) SendMsg(any) error {
+	return nil
+}
+
+func (fakeStream) RecvMsg(any) error {
+	return nil
+}
+
+var (
+	unaryInfo  = &grpc.UnaryServerInfo{FullMethod: "/orders.Orders/Get"}
+	streamInfo = &grpc.StreamServerInfo{FullMethod: "/orders.Orders/Watch", IsServerStream: true}
+	errFailed  = status.Error(codes.NotFound, "no such order")
+)
+
+func TestUnaryRecovery(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler grpc.UnaryHandler
+		want    codes.Code
+	}{
+		{"ok", func(ctx context.Context, req any) (any, error) { return req, nil }, codes.OK},
+		{"error", func(ctx context.Context, req any) (any, error) { return nil, errFailed }, codes.NotFound},
+		{"panic", func(ctx context.Context, req any) (any, error) { panic("boom") }, codes.Internal},
+		{"panic with error", func(ctx context.Context, req any) (any, error) { panic(errors.New("boom")) }, codes.Internal},
+		{"nil map write", func(ctx context.Context, req any) (any, error) {
+			var m map[string]int
+			m["boom"]++
+			return req, nil
+		}, codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := UnaryRecovery()(context.Background(), "req", unaryInfo, tt.handler)
+			if status.Code(err) != tt.want {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+			if tt.want == codes.OK && res != "req" {
+				t.Errorf("got response %v, want req", res)
+			}
+			if tt.want != codes.OK && res != nil {
+				t.Errorf("got response %v with error %v", res, err)
+			}
+		})
+	}
+}
+
+func TestStreamRecovery(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler grpc.StreamHandler
+		want    codes.Code
+	}{
+		{"ok", func(srv any, ss grpc.ServerStream) error { return ss.SendMsg("res") }, codes.OK},
+		{"error", func(srv any, ss grpc.ServerStream) error { return errFailed }, codes.NotFound},
+		{"panic", func(srv any, ss grpc.ServerStream) error { panic("boom") }, codes.Internal},
+		{"panic after sending", func(srv any, ss grpc.ServerStream) error {
+			_ = ss.SendMsg("res")
+			panic("boom")
+		}, codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := StreamRecovery()(nil, fakeStream{}, streamInfo, tt.handler)
+			if status.Code(err) != tt.want {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestMetricsRecordRecoveredPanics chains the interceptors as the server does
+// and checks that a panic is counted as an Internal error.
+func TestMetricsRecordRecoveredPanics(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry)
+	unary := []grpc.UnaryServerInterceptor{m.UnaryServerInterceptor(), UnaryLogging(), UnaryRecovery()}
+	stream := []grpc.StreamServerInterceptor{m.StreamServerInterceptor(), StreamLogging(), StreamRecovery()}
+
+	unaryHandler := func(ctx context.Context, req any) (any, error) { panic("boom") }
+	for i := len(unary) - 1; i >= 0; i-- {
+		next, intercept := unaryHandler, unary[i]
+		unaryHandler = func(ctx context.Context, req any) (any, error) {
+			return intercept(ctx, req, unaryInfo, next)
+		}
+	}
+	if _, err := unaryHandler(context.Background(), "req"); status.Code(err) != codes.Internal {
+		t.Fatalf("unary call got %v, want Internal", err)
+	}
+
+	streamHandler := func(srv any, ss grpc.ServerStream) error {
+		_ = ss.SendMsg("res")
+		panic("boom")
+	}
+	for i := len(stream) - 1; i >= 0; i-- {
+		next, intercept := streamHandler, stream[i]
+		streamHandler = func(srv any, ss grpc.ServerStream) error {
+			return intercept(srv, ss, streamInfo, next)
+		}
+	}
+	if err := streamHandler(nil, fakeStream{}); status.Code(err) != codes.Internal {
+		t.Fatalf("stream got %v, want Internal", err)
+	}
+
+	var b strings.Builder
+	if err := registry.Write(&b); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		`grpc_server_handled_total{grpc_type="unary",grpc_method="/orders.Orders/Get",grpc_code="Internal"} 1`,
+		`grpc_server_handled_total{grpc_type="server_stream",grpc_method="/orders.Orders/Watch",grpc_code="Internal"} 1`,
+		`grpc_server_msg_sent_total{grpc_type="server_stream",grpc_method="/orders.Orders/Watch"} 1`,
+		`grpc_server_msg_sent_total{grpc_type="unary",grpc_method="/orders.Orders/Get"} 0`,
+	} {
+		if !strings.Contains(b.String(), line+"\n") {
+			t.Errorf("metrics lack %s in\n%s", line, b.String())
+		}
+	}
+}
+
+func TestStreamTracker(t *testing.T) {
+	tracker := NewStreamTracker()
+	select {
+	case <-tracker.Idle():
+	default:
+		t.Fatal("new tracker is not idle")
+	}
+
+	release := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- tracker.StreamServerInterceptor()(nil, fakeStream{}, streamInfo, func(any, grpc.ServerStream) error {
+			<-release
+			return nil
+		})
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for tracker.Active() != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("stream was not tracked")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	idle := tracker.Idle()
+	select {
+	case <-idle:
+		t.Fatal("tracker is idle with a stream in flight")
+	default:
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-idle:
+	case <-time.After(5 * time.Second):
+		t.Fatal("tracker did not become idle")
+	}
+	if n := tracker.Active(); n != 0 {
+		t.Errorf("%d streams active, want 0", n)
+	}
+}
@@ -0,0 +1,78 @@
+package interceptor
+
+import (
+	"context"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"dist-grpc/pkg/auth"
+)
+
+type identityStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *identityStream) Context() context.Context {
+	return s.ctx
+}
+
+func logCall(method, kind string, ctx context.Context, id *auth.Identity, start time.Time, received, sent int64, err error) {
+	code := status.Code(err)
+	identity := "anonymous"
+	if id.Subject != "" {
+		identity = id.String()
+	}
+	fields := []any{
+		"method", method,
+		"type", kind,
+		"peer", peerAddr(ctx),
+		"identity", identity,
+		"code", code,
+		"duration", time.Since(start),
+		"received", received,
+		"sent", sent,
+	}
+	switch code {
+	case codes.OK:
+		log.Info("Handled RPC", fields...)
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unimplemented:
+		log.Error("Handled RPC", append(fields, "err", err)...)
+	default:
+		log.Warn("Handled RPC", append(fields, "err", err)...)
+	}
+}
+
+// UnaryLogging logs one line per call. It should run before authentication so
+// that rejected calls are logged as well; the identity is picked up afterwards.
+func UnaryLogging() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		ctx, id := auth.WithIdentitySlot(ctx)
+		res, err := handler(ctx, req)
+		logCall(info.FullMethod, Unary, ctx, id, start, 1, btoi(err == nil), err)
+		return res, err
+	}
+}
+
+func StreamLogging() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx, id := auth.WithIdentitySlot(ss.Context())
+		stream := &countingStream{ServerStream: &identityStream{ServerStream: ss, ctx: ctx}}
+		err := handler(srv, stream)
+		logCall(info.FullMethod, streamType(info), ctx, id, start, stream.received.Load(), stream.sent.Load(), err)
+		return err
+	}
+}
+
+func btoi(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,58 @@
+package interceptor
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"dist-grpc/pkg/metrics"
+)
+
+type Metrics struct {
+	started  *metrics.Counter
+	handled  *metrics.Counter
+	received *metrics.Counter
+	sent     *metrics.Counter
+	latency  *metrics.Histogram
+}
+
+func NewMetrics(reg *metrics.Registry) *Metrics {
+	return &Metrics{
+		started:  reg.NewCounter("grpc_server_started_total", "Total number of RPCs started on the server.", "grpc_type", "grpc_method"),
+		handled:  reg.NewCounter("grpc_server_handled_total", "Total number of RPCs completed on the server, regardless of success or failure.", "grpc_type", "grpc_method", "grpc_code"),
+		received: reg.NewCounter("grpc_server_msg_received_total", "Total number of stream messages received from the client.", "grpc_type", "grpc_method"),
+		sent:     reg.NewCounter("grpc_server_msg_sent_total", "Total number of stream messages sent by the server.", "grpc_type", "grpc_method"),
+		latency:  reg.NewHistogram("grpc_server_handling_seconds", "Histogram of response latency of RPCs handled by the server.", metrics.DefaultBuckets, "grpc_type", "grpc_method"),
+	}
+}
+
+func (m *Metrics) record(method, kind string, start time.Time, received, sent int64, err error) {
+	m.handled.Inc(kind, method, status.Code(err).String())
+	m.received.Add(float64(received), kind, method)
+	m.sent.Add(float64(sent), kind, method)
+	m.latency.Observe(time.Since(start).Seconds(), kind, method)
+}
+
+func (m *Metrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		m.started.Inc(Unary, info.FullMethod)
+		res, err := handler(ctx, req)
+		m.record(info.FullMethod, Unary, start, 1, btoi(err == nil), err)
+		return res, err
+	}
+}
+
+func (m *Metrics) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		kind := streamType(info)
+		m.started.Inc(kind, info.FullMethod)
+		stream := &countingStream{ServerStream: ss}
+		err := handler(srv, stream)
+		m.record(info.FullMethod, kind, start, stream.received.Load(), stream.sent.Load(), err)
+		return err
+	}
+}
@@ -0,0 +1,41 @@
+package interceptor
+
+import (
+	"context"
+	"runtime/debug"
+
+	"github.com/charmbracelet/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func recovered(method string, p any) error {
+	log.Error("Recovered from panic", "method", method, "panic", p, "stack", string(debug.Stack()))
+	return status.Error(codes.Internal, "internal error")
+}
+
+// UnaryRecovery turns a panicking handler into an Internal error instead of
+// taking the whole server down. It should run after the logging and metrics
+// interceptors so that they record the failure.
+func UnaryRecovery() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (res any, err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				res, err = nil, recovered(info.FullMethod, p)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+func StreamRecovery() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = recovered(info.FullMethod, p)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,60 @@
+package interceptor
+
+import (
+	"sync"
+
+	"google.golang.org/grpc"
+)
+
+// StreamTracker keeps count of the streams in flight so that a shutdown can
+// wait for them and report how many finished on their own.
+type StreamTracker struct {
+	active int
+	idle   chan struct{}
+	mu     sync.Mutex
+}
+
+func NewStreamTracker() *StreamTracker {
+	idle := make(chan struct{})
+	close(idle)
+	return &StreamTracker{idle: idle}
+}
+
+func (t *StreamTracker) Active() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.active
+}
+
+// Idle returns a channel that is closed once no stream is in flight.
+func (t *StreamTracker) Idle() <-chan struct{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.idle
+}
+
+func (t *StreamTracker) begin() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.active == 0 {
+		t.idle = make(chan struct{})
+	}
+	t.active++
+}
+
+func (t *StreamTracker) end() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.active--
+	if t.active == 0 {
+		close(t.idle)
+	}
+}
+
+func (t *StreamTracker) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		t.begin()
+		defer t.end()
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,234 @@
+package matcher
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"dist-grpc/pkg/catalog"
+)
+
+const gramSize = 3
+
+type postings map[string]struct{}
+
+type indexEntry struct {
+	order catalog.Order
+	seq   uint64
+}
+
+// Index is an inverted index over the lowercased order names. Trigram
+// postings narrow substring and prefix queries down to a candidate set,
+// word postings answer token queries and a name table answers exact ones.
+// Candidates are always confirmed with the mode's scorer, so the index only
+// decides which orders are looked at, never whether they match.
+type Index struct {
+	entries map[string]*indexEntry
+	grams   map[string]postings
+	tokens  map[string]postings
+	names   map[string]postings
+	nextSeq uint64
+	mu      sync.RWMutex
+}
+
+func NewIndex(orders []catalog.Order) *Index {
+	idx := &Index{
+		entries: make(map[string]*indexEntry),
+		grams:   make(map[string]postings),
+		tokens:  make(map[string]postings),
+		names:   make(map[string]postings),
+	}
+	for _, order := range orders {
+		idx.put(order)
+	}
+	return idx
+}
+
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.entries)
+}
+
+func (idx *Index) Apply(change catalog.Change) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	switch change.Kind {
+	case catalog.Added, catalog.Updated:
+		idx.put(change.New)
+	case catalog.Removed:
+		idx.remove(change.Old.ID)
+	}
+}
+
+func (idx *Index) put(order catalog.Order) {
+	seq := idx.nextSeq
+	if old, ok := idx.entries[order.ID]; ok {
+		seq = old.seq
+		idx.unlink(old.order)
+	} else {
+		idx.nextSeq++
+	}
+	idx.entries[order.ID] = &indexEntry{order: order, seq: seq}
+	lower := strings.ToLower(order.Name)
+	for _, gram := range grams(lower) {
+		link(idx.grams, gram, order.ID)
+	}
+	for _, token := range strings.Fields(lower) {
+		link(idx.tokens, token, order.ID)
+	}
+	link(idx.names, order.Name, order.ID)
+}
+
+func (idx *Index) remove(id string) {
+	if old, ok := idx.entries[id]; ok {
+		idx.unlink(old.order)
+		delete(idx.entries, id)
+	}
+}
+
+func (idx *Index) unlink(order catalog.Order) {
+	lower := strings.ToLower(order.Name)
+	for _, gram := range grams(lower) {
+		unlink(idx.grams, gram, order.ID)
+	}
+	for _, token := range strings.Fields(lower) {
+		unlink(idx.tokens, token, order.ID)
+	}
+	unlink(idx.names, order.Name, order.ID)
+}
+
+func (idx *Index) Search(query string, mode Mode) []Result {
+	result, _ := idx.SearchContext(context.Background(), query, mode)
+	return result
+}
+
+func (idx *Index) SearchContext(ctx context.Context, query string, mode Mode) ([]Result, error) {
+	q := newQuery(query)
+	score := mode.scorer()
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var entries []*indexEntry
+	if ids, ok := idx.candidates(q, mode); ok {
+		for id := range ids {
+			entries = append(entries, idx.entries[id])
+		}
+	} else {
+		for _, e := range idx.entries {
+			entries = append(entries, e)
+		}
+	}
+
+	var matched []*indexEntry
+	scores := make(map[*indexEntry]float64)
+	for i, e := range entries {
+		if i%checkInterval == 0 && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if s, ok := score(q, e.order.Name); ok {
+			matched = append(matched, e)
+			scores[e] = s
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		if scores[matched[i]] != scores[matched[j]] {
+			return scores[matched[i]] > scores[matched[j]]
+		}
+		return matched[i].seq < matched[j].seq
+	})
+	result := make([]Result, 0, len(matched))
+	for _, e := range matched {
+		result = append(result, Result{Order: e.order, Score: scores[e]})
+	}
+	return result, nil
+}
+
+// candidates returns the ids that can possibly match, or false when the
+// query cannot be narrowed down and every entry has to be scored.
+func (idx *Index) candidates(q query, mode Mode) (postings, bool) {
+	switch mode {
+	case Exact:
+		return idx.names[q.raw], true
+	case Token:
+		if len(q.tokens) == 0 {
+			return nil, true
+		}
+		var lists []postings
+		for _, token := range q.tokens {
+			lists = append(lists, idx.tokens[token])
+		}
+		return intersect(lists), true
+	case Substring, CaseInsensitive, Prefix:
+		gs := grams(q.lower)
+		if len(gs) == 0 {
+			return nil, false
+		}
+		var lists []postings
+		for _, gram := range gs {
+			lists = append(lists, idx.grams[gram])
+		}
+		return intersect(lists), true
+	default:
+		return nil, false
+	}
+}
+
+func grams(s string) []string {
+	runes := []rune(s)
+	if len(runes) < gramSize {
+		return nil
+	}
+	var res []string
+	seen := make(map[string]bool)
+	for i := 0; i+gramSize <= len(runes); i++ {
+		gram := string(runes[i : i+gramSize])
+		if !seen[gram] {
+			seen[gram] = true
+			res = append(res, gram)
+		}
+	}
+	return res
+}
+
+func link(index map[string]postings, key, id string) {
+	p, ok := index[key]
+	if !ok {
+		p = make(postings)
+		index[key] = p
+	}
+	p[id] = struct{}{}
+}
+
+func unlink(index map[string]postings, key, id string) {
+	if p, ok := index[key]; ok {
+		delete(p, id)
+		if len(p) == 0 {
+			delete(index, key)
+		}
+	}
+}
+
+func intersect(lists []postings) postings {
+	if len(lists) == 0 {
+		return nil
+	}
+	sort.Slice(lists, func(i, j int) bool {
+		return len(lists[i]) < len(lists[j])
+	})
+	res := make(postings)
+	for id := range lists[0] {
+		found := true
+		for _, p := range lists[1:] {
+			if _, ok := p[id]; !ok {
+				found = false
+				break
+			}
+		}
+		if found {
+			res[id] = struct{}{}
+		}
+	}
+	return res
+}
@@ -0,0 +1,122 @@
+package matcher
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"dist-grpc/pkg/catalog"
+)
+
+var (
+	adjectives = []string{"fresh", "organic", "dried", "frozen", "sliced", "ripe", "juicy", "sweet", "sour", "wild"}
+	colors     = []string{"red", "green", "yellow", "golden", "purple", "black", "white", "pink", "blue", "orange"}
+	nouns      = []string{"apple", "banana", "cherry", "grape", "kiwi", "mango", "pear", "plum", "peach", "melon"}
+)
+
+type matchCase struct {
+	mode  Mode
+	query string
+}
+
+// matchCases covers every mode. The exact query is left empty, to be set to
+// a name of the catalog.
+var matchCases = []matchCase{
+	{Exact, ""},
+	{Substring, "apple 42"},
+	{Substring, "an"},
+	{CaseInsensitive, "Golden Mango"},
+	{Prefix, "organic"},
+	{Prefix, "fr"},
+	{Token, "purple plum"},
+	{Fuzzy, "aple"},
+}
+
+func generateCatalog(size int, seed int64) []catalog.Order {
+	r := rand.New(rand.NewSource(seed))
+	orders := make([]catalog.Order, size)
+	for i := range orders {
+		orders[i] = catalog.Order{
+			ID: strconv.Itoa(i),
+			Name: fmt.Sprintf("%s %s %s %d",
+				adjectives[r.Intn(len(adjectives))],
+				colors[r.Intn(len(colors))],
+				nouns[r.Intn(len(nouns))],
+				r.Intn(size)),
+		}
+	}
+	return orders
+}
+
+func expectSameResults(t *testing.T, c matchCase, scan, index []Result) {
+	t.Helper()
+	if len(scan) != len(index) {
+		t.Fatalf("%s %q: scan matched %d orders, index %d", c.mode, c.query, len(scan), len(index))
+	}
+	for i := range scan {
+		if scan[i].Order.ID != index[i].Order.ID || scan[i].Score != index[i].Score {
+			t.Fatalf("%s %q: result %d is %v from the scan, %v from the index", c.mode, c.query, i, scan[i], index[i])
+		}
+	}
+}
+
+// TestIndexMatchesScan checks that the index returns what the linear scan
+// does for the same queries, also once the catalog changed.
+func TestIndexMatchesScan(t *testing.T) {
+	orders := generateCatalog(2000, 1)
+	store := catalog.NewMemoryStore(orders)
+	linear := NewLinear(store)
+	indexed := New(store)
+	defer indexed.Close()
+
+	check := func() {
+		t.Helper()
+		for _, c := range matchCases {
+			if c.query == "" {
+				c.query = orders[len(orders)/2].Name
+			}
+			expectSameResults(t, c, linear.Match(c.query, c.mode), indexed.Match(c.query, c.mode))
+		}
+	}
+	check()
+
+	if err := store.Add(catalog.Order{ID: "new", Name: "organic golden mango 42"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Update(catalog.Order{ID: "7", Name: "purple plum apple 42"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete(orders[len(orders)/2].ID); err != nil {
+		t.Fatal(err)
+	}
+	check()
+}
+
+func benchmarkMatch(b *testing.B, newMatcher func(catalog.Store) *Matcher) {
+	for _, size := range []int{1000, 10000, 100000} {
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			orders := generateCatalog(size, 1)
+			m := newMatcher(catalog.NewMemoryStore(orders))
+			defer m.Close()
+			for _, c := range matchCases {
+				if c.query == "" {
+					c.query = orders[size/2].Name
+				}
+				b.Run(fmt.Sprintf("%s/%s", c.mode, c.query), func(b *testing.B) {
+					for i := 0; i < b.N; i++ {
+						m.Match(c.query, c.mode)
+					}
+				})
+			}
+		})
+	}
+}
+
+func BenchmarkIndex(b *testing.B) {
+	benchmarkMatch(b, New)
+}
+
+func BenchmarkScan(b *testing.B) {
+	benchmarkMatch(b, NewLinear)
+}
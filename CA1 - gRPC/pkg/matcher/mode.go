@@ -0,0 +1,201 @@
+package matcher
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+type Mode int
+
+const (
+	Substring Mode = iota
+	Exact
+	CaseInsensitive
+	Prefix
+	Token
+	Fuzzy
+)
+
+var modeNames = map[Mode]string{
+	Substring:       "substring",
+	Exact:           "exact",
+	CaseInsensitive: "case-insensitive",
+	Prefix:          "prefix",
+	Token:           "token",
+	Fuzzy:           "fuzzy",
+}
+
+func (m Mode) String() string {
+	if name, ok := modeNames[m]; ok {
+		return name
+	}
+	return fmt.Sprintf("Mode(%d)", int(m))
+}
+
+func ParseMode(name string) (Mode, error) {
+	for mode, v := range modeNames {
+		if v == name {
+			return mode, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown match mode: %s", name)
+}
+
+type query struct {
+	raw    string
+	lower  string
+	tokens []string
+}
+
+func newQuery(raw string) query {
+	lower := strings.ToLower(raw)
+	return query{raw: raw, lower: lower, tokens: strings.Fields(lower)}
+}
+
+type scorer func(q query, name string) (float64, bool)
+
+func (m Mode) scorer() scorer {
+	switch m {
+	case Exact:
+		return scoreExact
+	case CaseInsensitive:
+		return scoreCaseInsensitive
+	case Prefix:
+		return scorePrefix
+	case Token:
+		return scoreToken
+	case Fuzzy:
+		return scoreFuzzy
+	default:
+		return scoreSubstring
+	}
+}
+
+// Scorer returns a function scoring single order names against the query
+// as Match does, for checking orders one at a time as they change.
+func Scorer(query string, mode Mode) func(name string) (float64, bool) {
+	q := newQuery(query)
+	score := mode.scorer()
+	return func(name string) (float64, bool) {
+		return score(q, name)
+	}
+}
+
+// coverage is the share of the name taken up by the match, so that "apple"
+// ranks above "green apple" for the query "apple".
+func coverage(q, name string) float64 {
+	n := utf8.RuneCountInString(name)
+	if n == 0 {
+		return 1
+	}
+	return float64(utf8.RuneCountInString(q)) / float64(n)
+}
+
+func scoreExact(q query, name string) (float64, bool) {
+	return 1, q.raw == name
+}
+
+func scoreSubstring(q query, name string) (float64, bool) {
+	if !strings.Contains(name, q.raw) {
+		return 0, false
+	}
+	return coverage(q.raw, name), true
+}
+
+func scoreCaseInsensitive(q query, name string) (float64, bool) {
+	if !strings.Contains(strings.ToLower(name), q.lower) {
+		return 0, false
+	}
+	return coverage(q.lower, name), true
+}
+
+func scorePrefix(q query, name string) (float64, bool) {
+	lower := strings.ToLower(name)
+	if strings.HasPrefix(lower, q.lower) {
+		return coverage(q.lower, name), true
+	}
+	for _, token := range strings.Fields(lower) {
+		if strings.HasPrefix(token, q.lower) {
+			return coverage(q.lower, name) / 2, true
+		}
+	}
+	return 0, false
+}
+
+// scoreToken requires every query word to appear as a whole word of the name
+// and scores by the fraction of the name's words that were asked for.
+func scoreToken(q query, name string) (float64, bool) {
+	if len(q.tokens) == 0 {
+		return 0, false
+	}
+	words := make(map[string]bool)
+	nameTokens := strings.Fields(strings.ToLower(name))
+	for _, token := range nameTokens {
+		words[token] = true
+	}
+	for _, token := range q.tokens {
+		if !words[token] {
+			return 0, false
+		}
+	}
+	return float64(len(q.tokens)) / float64(len(nameTokens)), true
+}
+
+// scoreFuzzy accepts names whose whole text or any single word is within a
+// length-dependent edit distance of the query.
+func scoreFuzzy(q query, name string) (float64, bool) {
+	if q.lower == "" {
+		return 0, false
+	}
+	lower := strings.ToLower(name)
+	limit := maxEdits(q.lower)
+	best := -1.0
+	candidates := append([]string{lower}, strings.Fields(lower)...)
+	for i, candidate := range candidates {
+		d := levenshtein(q.lower, candidate)
+		if d > limit {
+			continue
+		}
+		s := 1 - float64(d)/float64(max(utf8.RuneCountInString(q.lower), utf8.RuneCountInString(candidate)))
+		if i > 0 {
+			s *= coverage(candidate, lower)
+		}
+		best = max(best, s)
+	}
+	return best, best >= 0
+}
+
+func maxEdits(q string) int {
+	switch n := utf8.RuneCountInString(q); {
+	case n < 3:
+		return 0
+	case n < 6:
+		return 1
+	case n < 10:
+		return 2
+	default:
+		return 3
+	}
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,76 @@
+package matcher
+
+import (
+	"math"
+	"testing"
+
+	"dist-grpc/pkg/catalog"
+)
+
+var modeOrders = []catalog.Order{
+	{ID: "1", Name: "Apple"},
+	{ID: "2", Name: "apple pie"},
+	{ID: "3", Name: "green apple"},
+	{ID: "4", Name: "pineapple"},
+	{ID: "5", Name: "Banana"},
+}
+
+type scored struct {
+	id    string
+	score float64
+}
+
+func TestModes(t *testing.T) {
+	tests := []struct {
+		mode  Mode
+		query string
+		want  []scored
+	}{
+		{Exact, "apple", nil},
+		{Exact, "Apple", []scored{{"1", 1}}},
+		{Substring, "apple", []scored{{"2", 5.0 / 9}, {"4", 5.0 / 9}, {"3", 5.0 / 11}}},
+		{Substring, "Apple", []scored{{"1", 1}}},
+		{CaseInsensitive, "apple", []scored{{"1", 1}, {"2", 5.0 / 9}, {"4", 5.0 / 9}, {"3", 5.0 / 11}}},
+		{CaseInsensitive, "APPLE", []scored{{"1", 1}, {"2", 5.0 / 9}, {"4", 5.0 / 9}, {"3", 5.0 / 11}}},
+		{Prefix, "app", []scored{{"1", 3.0 / 5}, {"2", 3.0 / 9}, {"3", 3.0 / 11 / 2}}},
+		{Prefix, "pine", []scored{{"4", 4.0 / 9}}},
+		{Token, "apple", []scored{{"1", 1}, {"2", 1.0 / 2}, {"3", 1.0 / 2}}},
+		{Token, "Apple Green", []scored{{"3", 1}}},
+		{Token, "appl", nil},
+		{Token, "", nil},
+		{Fuzzy, "aple", []scored{{"1", 0.8}, {"2", 0.8 * 5 / 9}, {"3", 0.8 * 5 / 11}}},
+		{Fuzzy, "bananna", []scored{{"5", 1 - 1.0/7}}},
+		{Fuzzy, "ap", nil},
+	}
+	store := catalog.NewMemoryStore(modeOrders)
+	indexed := New(store)
+	defer indexed.Close()
+	matchers := map[string]*Matcher{"scan": NewLinear(store), "index": indexed}
+	for name, m := range matchers {
+		for _, tt := range tests {
+			got := m.Match(tt.query, tt.mode)
+			if len(got) != len(tt.want) {
+				t.Errorf("%s: %s %q matched %v, want %v", name, tt.mode, tt.query, got, tt.want)
+				continue
+			}
+			for i, r := range got {
+				if r.Order.ID != tt.want[i].id || math.Abs(r.Score-tt.want[i].score) > 1e-9 {
+					t.Errorf("%s: %s %q result %d is %s with %v, want %s with %v",
+						name, tt.mode, tt.query, i, r.Order.ID, r.Score, tt.want[i].id, tt.want[i].score)
+				}
+			}
+		}
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	for mode, name := range modeNames {
+		got, err := ParseMode(name)
+		if err != nil || got != mode {
+			t.Errorf("ParseMode(%q) = %v, %v, want %v", name, got, err, mode)
+		}
+	}
+	if _, err := ParseMode("regex"); err == nil {
+		t.Error("ParseMode accepted an unknown mode")
+	}
+}
@@ -1,28 +1,142 @@
 package matcher
 
 import (
-	"strings"
+	"context"
+	"sort"
+
+	"dist-grpc/pkg/catalog"
 )
 
-var serverOrders = []string{
-	"banana",
-	"apple",
-	"orange",
-	"grape",
-	"red apple",
-	"kiwi",
-	"mango",
-	"pear",
-	"cherry",
-	"green apple",
-}
-
-func MatchOrder(order string) []string {
-	var result []string
-	for _, serverOrder := range serverOrders {
-		if strings.Contains(serverOrder, order) {
-			result = append(result, serverOrder)
+type Result struct {
+	Order catalog.Order
+	Score float64
+}
+
+type Matcher struct {
+	store       catalog.Store
+	index       *Index
+	unsubscribe func()
+}
+
+// New returns a matcher answering from an inverted index that follows the
+// store's changes.
+func New(store catalog.Store) *Matcher {
+	m := &Matcher{store: store, index: NewIndex(nil)}
+	m.unsubscribe = store.Subscribe(m.index.Apply)
+	return m
+}
+
+// NewLinear returns a matcher that scans the whole store for every query.
+func NewLinear(store catalog.Store) *Matcher {
+	return &Matcher{store: store}
+}
+
+func (m *Matcher) Close() {
+	if m.unsubscribe != nil {
+		m.unsubscribe()
+	}
+}
+
+// checkInterval is how many orders are scored between checks of the
+// context, so that abandoned queries over large catalogs stop early.
+const checkInterval = 256
+
+// Match returns the catalog orders matching the query under the given mode,
+// ordered by descending score. Orders with equal scores keep catalog order.
+func (m *Matcher) Match(query string, mode Mode) []Result {
+	result, _ := m.MatchContext(context.Background(), query, mode)
+	return result
+}
+
+// MatchContext is Match that gives up with the context's error once the
+// context is done.
+func (m *Matcher) MatchContext(ctx context.Context, query string, mode Mode) ([]Result, error) {
+	if m.index != nil {
+		return m.index.SearchContext(ctx, query, mode)
+	}
+	score := mode.scorer()
+	q := newQuery(query)
+	var result []Result
+	for i, order := range m.store.List() {
+		if i%checkInterval == 0 && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if s, ok := score(q, order.Name); ok {
+			result = append(result, Result{Order: order, Score: s})
+		}
+	}
+	sortResults(result)
+	return result, nil
+}
+
+func (m *Matcher) MatchOrder(ctx context.Context, order string) ([]string, error) {
+	result, err := m.MatchContext(ctx, order, Substring)
+	return Names(result), err
+}
+
+func Names(results []Result) []string {
+	var names []string
+	for _, r := range results {
+		names = append(names, r.Order.Name)
+	}
+	return names
+}
+
+func Scores(results []Result) []float64 {
+	var scores []float64
+	for _, r := range results {
+		scores = append(scores, r.Score)
+	}
+	return scores
+}
+
+func sortResults(results []Result) {
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+}
+
+// Merge drops repeated orders, keeping the best score of each, and re-sorts
+// the combined results of several queries.
+func Merge(results []Result) []Result {
+	index := make(map[string]int)
+	var merged []Result
+	for _, r := range results {
+		if i, ok := index[r.Order.ID]; ok {
+			merged[i].Score = max(merged[i].Score, r.Score)
+			continue
+		}
+		index[r.Order.ID] = len(merged)
+		merged = append(merged, r)
+	}
+	sortResults(merged)
+	return merged
+}
+
+// Intersect keeps the orders found in every list, each with its lowest
+// score, as the combined result of queries that must all match.
+func Intersect(lists ...[]Result) []Result {
+	if len(lists) == 0 {
+		return nil
+	}
+	seen := make(map[string]int)
+	lowest := make(map[string]float64)
+	for _, list := range lists {
+		for _, r := range list {
+			if score, ok := lowest[r.Order.ID]; ok {
+				lowest[r.Order.ID] = min(score, r.Score)
+			} else {
+				lowest[r.Order.ID] = r.Score
+			}
+			seen[r.Order.ID]++
+		}
+	}
+	var result []Result
+	for _, r := range lists[0] {
+		if seen[r.Order.ID] == len(lists) {
+			result = append(result, Result{Order: r.Order, Score: lowest[r.Order.ID]})
 		}
 	}
+	sortResults(result)
 	return result
 }
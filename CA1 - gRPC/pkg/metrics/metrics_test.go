@@ -0,0 +1,128 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func expectOutput(t *testing.T, r *Registry, want string) {
+	t.Helper()
+	var b strings.Builder
+	if err := r.Write(&b); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCounter(t *testing.T) {
+	r := NewRegistry()
+	plain := r.NewCounter("requests_total", "Requests served.")
+	labelled := r.NewCounter("rpcs_total", "RPCs by method and code.", "method", "code")
+	plain.Inc()
+	plain.Add(2.5)
+	labelled.Inc("Get", "OK")
+	labelled.Inc("Get", "OK")
+	labelled.Inc("Get", "NotFound")
+	labelled.Add(3, "Add", "OK")
+
+	expectOutput(t, r, `# HELP requests_total Requests served.
+# TYPE requests_total counter
+requests_total 3.5
+# HELP rpcs_total RPCs by method and code.
+# TYPE rpcs_total counter
+rpcs_total{method="Add",code="OK"} 3
+rpcs_total{method="Get",code="NotFound"} 1
+rpcs_total{method="Get",code="OK"} 2
+`)
+}
+
+func TestCounterPanics(t *testing.T) {
+	c := NewRegistry().NewCounter("c", "C.", "a")
+	for name, f := range map[string]func(){
+		"negative delta":     func() { c.Add(-1, "x") },
+		"missing label":      func() { c.Inc() },
+		"extra label values": func() { c.Inc("x", "y") },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	r := NewRegistry()
+	h := r.NewHistogram("latency_seconds", "Latency.", []float64{1, 0.1, 0.5}, "method")
+	for _, v := range []float64{0.05, 0.1, 0.3, 2} {
+		h.Observe(v, "Get")
+	}
+	h.Observe(0.75, "Add")
+
+	expectOutput(t, r, `# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{method="Add",le="0.1"} 0
+latency_seconds_bucket{method="Add",le="0.5"} 0
+latency_seconds_bucket{method="Add",le="1"} 1
+latency_seconds_bucket{method="Add",le="+Inf"} 1
+latency_seconds_sum{method="Add"} 0.75
+latency_seconds_count{method="Add"} 1
+latency_seconds_bucket{method="Get",le="0.1"} 2
+latency_seconds_bucket{method="Get",le="0.5"} 3
+latency_seconds_bucket{method="Get",le="1"} 3
+latency_seconds_bucket{method="Get",le="+Inf"} 4
+latency_seconds_sum{method="Get"} 2.45
+latency_seconds_count{method="Get"} 4
+`)
+}
+
+func TestHistogramWithoutLabels(t *testing.T) {
+	r := NewRegistry()
+	h := r.NewHistogram("size_bytes", "Size.", []float64{10})
+	h.Observe(4)
+	h.Observe(40)
+
+	expectOutput(t, r, `# HELP size_bytes Size.
+# TYPE size_bytes histogram
+size_bytes_bucket{le="10"} 1
+size_bytes_bucket{le="+Inf"} 2
+size_bytes_sum 44
+size_bytes_count 2
+`)
+}
+
+func TestEscaping(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounter("queries_total", "Queries by text,\nwith a \\ and \"quotes\".", "query")
+	c.Inc(`say "hi"`)
+	c.Inc(`C:\orders`)
+	c.Inc("two\nlines")
+	c.Inc("tab\there é")
+
+	expectOutput(t, r, `# HELP queries_total Queries by text,\nwith a \\ and "quotes".
+# TYPE queries_total counter
+queries_total{query="C:\\orders"} 1
+queries_total{query="say \"hi\""} 1
+queries_total{query="tab	here é"} 1
+queries_total{query="two\nlines"} 1
+`)
+}
+
+func TestHandler(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounter("requests_total", "Requests served.").Inc()
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("content type %q, want the text format", ct)
+	}
+	if !strings.Contains(rec.Body.String(), "\nrequests_total 1\n") {
+		t.Errorf("body %q lacks the counter", rec.Body.String())
+	}
+}
@@ -0,0 +1,122 @@
+package orderclient_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"dist-grpc/pkg/harness"
+	"dist-grpc/pkg/interceptor"
+	"dist-grpc/pkg/matcher"
+	"dist-grpc/pkg/orderclient"
+	"dist-grpc/pkg/server"
+)
+
+const balancedCalls = 12
+
+// dialBalanced resolves three replicas, each stamping its own instance id,
+// and returns a client balancing over them under the policy.
+func dialBalanced(t *testing.T, policy string, opts orderclient.Options) *orderclient.Client {
+	t.Helper()
+	replicas := make(map[string]*harness.Harness)
+	var addrs []string
+	for i := 0; i < 3; i++ {
+		id := fmt.Sprintf("replica-%d", i)
+		store := harness.NewFakeStore(harness.Fruits...)
+		h, err := harness.Start(store, matcher.New(store), server.Config{},
+			grpc.ChainUnaryInterceptor(interceptor.UnaryInstance(id)),
+			grpc.ChainStreamInterceptor(interceptor.StreamInstance(id)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(h.Close)
+		replicas[id] = h
+		addrs = append(addrs, id)
+	}
+
+	target, resolve := orderclient.Resolve(addrs)
+	conn, err := grpc.Dial(target, resolve,
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return replicas[addr].Connect(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultServiceConfig(fmt.Sprintf(`{"loadBalancingConfig": [{%q: {}}]}`, policy)),
+		// Round robin only spreads calls over the replicas it is connected
+		// to, so the calls wait for the connection to be ready.
+		grpc.WithBlock(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	opts.CallTimeout = harness.HandledTimeout
+	return orderclient.New(conn, opts)
+}
+
+// servedBy makes unary calls and returns the instances that served each of
+// their pages.
+func servedBy(t *testing.T, client *orderclient.Client) [][]string {
+	t.Helper()
+	var calls [][]string
+	for i := 0; i < balancedCalls; i++ {
+		pages, err := client.Unary(context.Background(), "apple")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var instances []string
+		for _, page := range pages {
+			instances = append(instances, page.Instance)
+		}
+		calls = append(calls, instances)
+	}
+	return calls
+}
+
+func TestRoundRobin(t *testing.T) {
+	served := make(map[string]int)
+	for _, instances := range servedBy(t, dialBalanced(t, "round_robin", orderclient.Options{})) {
+		for _, instance := range instances {
+			served[instance]++
+		}
+	}
+	if len(served) != 3 {
+		t.Fatalf("calls served by %v, want every replica", served)
+	}
+}
+
+func TestPickFirst(t *testing.T) {
+	served := make(map[string]int)
+	for _, instances := range servedBy(t, dialBalanced(t, "pick_first", orderclient.Options{})) {
+		for _, instance := range instances {
+			served[instance]++
+		}
+	}
+	if len(served) != 1 || served["replica-0"] != balancedCalls {
+		t.Fatalf("calls served by %v, want replica-0 only", served)
+	}
+}
+
+// TestPagesServedBy checks that the pages of a call, each fetched by its own
+// RPC, name the replica that served them rather than the last one.
+func TestPagesServedBy(t *testing.T) {
+	calls := servedBy(t, dialBalanced(t, "round_robin", orderclient.Options{PageSize: 1}))
+	spread := false
+	for _, instances := range calls {
+		if len(instances) != len(harness.AppleResults) {
+			t.Fatalf("got %d pages, want %d", len(instances), len(harness.AppleResults))
+		}
+		for _, instance := range instances {
+			if instance == "" {
+				t.Fatalf("pages served by %v, want every page to name its replica", instances)
+			}
+			spread = spread || instance != instances[0]
+		}
+	}
+	if !spread {
+		t.Fatalf("pages served by %v, want pages of a call served by several replicas", calls)
+	}
+}
@@ -0,0 +1,282 @@
+// Package orderclient calls the OrderManagement service with the four RPC
+// patterns, following pages and keeping requests of a stream numbered, and
+// hands back what the server answered without printing anything.
+package orderclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"dist-grpc/pkg/interceptor"
+	pb "dist-grpc/pkg/proto"
+)
+
+type Options struct {
+	Mode        pb.MatchMode
+	PageSize    int32
+	MaxResults  int32
+	Aggregation pb.Aggregation
+	// Consistency is how up to date the reads of a replicated catalog must
+	// be, stale reads being served from the local copy of any server.
+	Consistency pb.ReadConsistency
+	// Window is how many requests of a bidirectional stream may await their
+	// response before sending pauses.
+	Window int
+	// CallTimeout bounds every unary call and each page of a server stream.
+	CallTimeout time.Duration
+	// StreamTimeout bounds client and bidirectional streams, which may stay
+	// open while queries are typed; zero means no deadline.
+	StreamTimeout time.Duration
+}
+
+// Response is a response together with the id of the server instance that
+// sent it, empty when the server does not name itself.
+type Response struct {
+	*pb.Response
+	Instance string
+}
+
+type Client struct {
+	rpc       pb.OrderManagementClient
+	opts      Options
+	requestID atomic.Int64
+}
+
+func New(conn grpc.ClientConnInterface, opts Options) *Client {
+	return &Client{rpc: pb.NewOrderManagementClient(conn), opts: opts}
+}
+
+// RPC returns the generated client for the calls not wrapped here.
+func (c *Client) RPC() pb.OrderManagementClient {
+	return c.rpc
+}
+
+func (c *Client) NewRequest(query string) *pb.Request {
+	return &pb.Request{
+		RequestId:   strconv.FormatInt(c.requestID.Add(1), 10),
+		Query:       query,
+		Mode:        c.opts.Mode,
+		PageSize:    c.opts.PageSize,
+		MaxResults:  c.opts.MaxResults,
+		Aggregation: c.opts.Aggregation,
+		Consistency: c.opts.Consistency,
+	}
+}
+
+// NewOrderID returns the request for the order of the id, read with the
+// consistency of the options.
+func (c *Client) NewOrderID(id string) *pb.OrderID {
+	return &pb.OrderID{Id: id, Consistency: c.opts.Consistency}
+}
+
+// instance returns the server instance named in the header of an RPC.
+func instance(header metadata.MD) string {
+	if ids := header.Get(interceptor.InstanceKey); len(ids) > 0 {
+		return ids[0]
+	}
+	return ""
+}
+
+func streamInstance(stream grpc.ClientStream) string {
+	// A failed header is reported by the next Recv.
+	header, _ := stream.Header()
+	return instance(header)
+}
+
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// CallContext bounds a single call by the call timeout.
+func (c *Client) CallContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withTimeout(ctx, c.opts.CallTimeout)
+}
+
+// Unary sends the query as unary RPCs and returns every page of the answer.
+func (c *Client) Unary(ctx context.Context, query string) ([]Response, error) {
+	req := c.NewRequest(query)
+	var pages []Response
+	for {
+		callCtx, cancel := c.CallContext(ctx)
+		var header metadata.MD
+		res, err := c.rpc.GetOrderUnary(callCtx, req, grpc.Header(&header))
+		cancel()
+		if err != nil {
+			return pages, fmt.Errorf("failed to get order: %w", err)
+		}
+		pages = append(pages, Response{Response: res, Instance: instance(header)})
+		if res.NextPageToken == "" {
+			return pages, nil
+		}
+		req.PageToken = res.NextPageToken
+	}
+}
+
+// ServerStream sends the query as server streaming RPCs, one per page, and
+// hands every streamed response to handle as it arrives.
+func (c *Client) ServerStream(ctx context.Context, query string, handle func(Response)) error {
+	req := c.NewRequest(query)
+	for {
+		nextPageToken, err := c.receivePage(ctx, req, handle)
+		if err != nil {
+			return err
+		}
+		if nextPageToken == "" {
+			return nil
+		}
+		req.PageToken = nextPageToken
+	}
+}
+
+func (c *Client) receivePage(ctx context.Context, req *pb.Request, handle func(Response)) (string, error) {
+	ctx, cancel := c.CallContext(ctx)
+	defer cancel()
+	stream, err := c.rpc.GetOrderServerStream(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get order: %w", err)
+	}
+	served := streamInstance(stream)
+
+	nextPageToken := ""
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nextPageToken, nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to receive response: %w", err)
+		}
+		// Responses already buffered are dropped once the caller gives up.
+		if err := ctx.Err(); err != nil {
+			return "", fmt.Errorf("failed to receive response: %w", status.FromContextError(err).Err())
+		}
+		handle(Response{Response: resp, Instance: served})
+		nextPageToken = resp.NextPageToken
+	}
+}
+
+// ClientStream sends every query until the channel is closed and returns
+// the single combined response.
+func (c *Client) ClientStream(ctx context.Context, queries <-chan string) (Response, error) {
+	ctx, cancel := withTimeout(ctx, c.opts.StreamTimeout)
+	defer cancel()
+
+	stream, err := c.rpc.GetOrderClientStream(ctx)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	for query := range queries {
+		if err := stream.Send(c.NewRequest(query)); err == io.EOF {
+			// The stream was aborted, the reason is returned by CloseAndRecv.
+			break
+		} else if err != nil {
+			return Response{}, fmt.Errorf("failed to send request: %w", err)
+		}
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to receive response: %w", err)
+	}
+	return Response{Response: resp, Instance: streamInstance(stream)}, nil
+}
+
+// BiDi sends every query until the channel is closed and hands each
+// response to handle as it arrives.
+func (c *Client) BiDi(ctx context.Context, queries <-chan string, handle func(Response)) error {
+	ctx, cancel := withTimeout(ctx, c.opts.StreamTimeout)
+	defer cancel()
+
+	stream, err := c.rpc.GetOrderBiDiStream(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+
+	// credits holds a slot per request awaiting its response, so that no
+	// more than Window requests are in flight when responses are slow to
+	// arrive or to be handled.
+	credits := make(chan struct{}, max(c.opts.Window, 1))
+	errc := make(chan error, 1)
+	go func() {
+		served := streamInstance(stream)
+		for {
+			resp, err := stream.Recv()
+			if err == io.EOF {
+				errc <- nil
+				return
+			}
+			if err != nil {
+				errc <- fmt.Errorf("failed to receive response: %w", err)
+				return
+			}
+			handle(Response{Response: resp, Instance: served})
+			<-credits
+		}
+	}()
+
+	for query := range queries {
+		select {
+		case credits <- struct{}{}:
+		case err := <-errc:
+			if err == nil {
+				err = fmt.Errorf("stream closed by the server")
+			}
+			return err
+		}
+		req := c.NewRequest(query)
+		if err := stream.Send(req); err == io.EOF {
+			// The stream was aborted, the reason is reported by Recv.
+			return <-errc
+		} else if err != nil {
+			cancel()
+			<-errc
+			return fmt.Errorf("failed to send request: %w", err)
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		cancel()
+		<-errc
+		return fmt.Errorf("failed to close send: %w", err)
+	}
+	// Waiting for the receiver means handle is never called after BiDi
+	// returns.
+	return <-errc
+}
+
+// Watch hands the events of the query to handle until the context is done,
+// which is how a watch is normally stopped and is not reported as an error.
+func (c *Client) Watch(ctx context.Context, query string, handle func(*pb.OrderEvent)) error {
+	stream, err := c.rpc.WatchOrders(ctx, c.NewRequest(query))
+	if err != nil {
+		return fmt.Errorf("failed to watch orders: %w", err)
+	}
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF || ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to receive event: %w", err)
+		}
+		handle(event)
+	}
+}
+
+// Collect returns a handler gathering the responses into res.
+func Collect(res *[]Response) func(Response) {
+	return func(r Response) {
+		*res = append(*res, r)
+	}
+}
@@ -0,0 +1,70 @@
+package orderclient
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+)
+
+const resolverScheme = "orders"
+
+// ParseTargets splits a comma-separated list of addresses.
+func ParseTargets(list string) []string {
+	var addrs []string
+	for _, addr := range strings.Split(list, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+// ReadTargets reads a static resolver file holding an address per line.
+// Blank lines and lines starting with # are skipped.
+func ReadTargets(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var addrs []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			addrs = append(addrs, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no addresses in %s", path)
+	}
+	return addrs, nil
+}
+
+// Resolve returns a target that resolves to every address, along with the
+// dial option registering its resolver, so that the load balancing policy
+// of the service config spreads the RPCs over them. Each address is
+// verified against its own host name when dialing with TLS.
+func Resolve(addrs []string) (string, grpc.DialOption) {
+	state := resolver.State{}
+	for _, addr := range addrs {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			host = addr
+		}
+		state.Addresses = append(state.Addresses, resolver.Address{Addr: addr, ServerName: host})
+	}
+	r := manual.NewBuilderWithScheme(resolverScheme)
+	r.InitialState(state)
+	return resolverScheme + ":///replicas", grpc.WithResolvers(r)
+}
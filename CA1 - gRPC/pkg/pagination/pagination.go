@@ -0,0 +1,87 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid page token")
+	ErrInvalidSize  = errors.New("page size and max results must not be negative")
+)
+
+type Params struct {
+	PageSize   int
+	MaxResults int
+	PageToken  string
+	// Key identifies the result set the token was issued for, so a token
+	// cannot be replayed against a different query.
+	Key string
+}
+
+type Page[T any] struct {
+	Items         []T
+	NextPageToken string
+	TotalCount    int
+}
+
+// Paginate cuts the page described by p out of items. MaxResults caps the
+// result set before paging and a zero PageSize returns everything that is left.
+func Paginate[T any](items []T, p Params) (Page[T], error) {
+	if p.PageSize < 0 || p.MaxResults < 0 {
+		return Page[T]{}, ErrInvalidSize
+	}
+	total := len(items)
+	if p.MaxResults > 0 && p.MaxResults < total {
+		total = p.MaxResults
+	}
+	offset := 0
+	if p.PageToken != "" {
+		var err error
+		offset, err = decode(p.PageToken, p.Key)
+		if err != nil || offset > total {
+			return Page[T]{}, ErrInvalidToken
+		}
+	}
+	end := total
+	if p.PageSize > 0 && offset+p.PageSize < total {
+		end = offset + p.PageSize
+	}
+	page := Page[T]{Items: items[offset:end], TotalCount: total}
+	if end < total {
+		page.NextPageToken = encode(end, p.Key)
+	}
+	return page, nil
+}
+
+// checksum binds the offset to the key, so that neither can be changed
+// without the token being rejected.
+func checksum(offset, key string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(offset + ":" + key))
+	return strconv.FormatUint(uint64(h.Sum32()), 36)
+}
+
+func encode(offset int, key string) string {
+	n := strconv.Itoa(offset)
+	return base64.RawURLEncoding.EncodeToString([]byte(n + ":" + checksum(n, key)))
+}
+
+func decode(token, key string) (int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, err
+	}
+	offset, hash, ok := strings.Cut(string(data), ":")
+	if !ok || hash != checksum(offset, key) {
+		return 0, ErrInvalidToken
+	}
+	n, err := strconv.Atoi(offset)
+	if err != nil || n < 0 {
+		return 0, ErrInvalidToken
+	}
+	return n, nil
+}
@@ -0,0 +1,91 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"slices"
+	"testing"
+)
+
+var items = []int{0, 1, 2, 3, 4, 5, 6}
+
+func TestPaginate(t *testing.T) {
+	tests := []struct {
+		name      string
+		params    Params
+		want      []int
+		wantTotal int
+		wantNext  bool
+	}{
+		{"everything", Params{}, items, 7, false},
+		{"first page", Params{PageSize: 3, Key: "q"}, []int{0, 1, 2}, 7, true},
+		{"middle page", Params{PageSize: 3, PageToken: encode(3, "q"), Key: "q"}, []int{3, 4, 5}, 7, true},
+		{"last page", Params{PageSize: 3, PageToken: encode(6, "q"), Key: "q"}, []int{6}, 7, false},
+		{"rest of the items", Params{PageToken: encode(5, "q"), Key: "q"}, []int{5, 6}, 7, false},
+		{"offset at total", Params{PageSize: 3, PageToken: encode(7, "q"), Key: "q"}, []int{}, 7, false},
+		{"max results", Params{PageSize: 2, MaxResults: 3, PageToken: encode(2, "q"), Key: "q"}, []int{2}, 3, false},
+		{"max results above total", Params{MaxResults: 10}, items, 7, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := Paginate(items, tt.params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(page.Items, tt.want) || page.TotalCount != tt.wantTotal {
+				t.Errorf("got %v of %d, want %v of %d", page.Items, page.TotalCount, tt.want, tt.wantTotal)
+			}
+			if (page.NextPageToken != "") != tt.wantNext {
+				t.Errorf("next page token %q, want one: %v", page.NextPageToken, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestPaginateFollowsTokens(t *testing.T) {
+	var got []int
+	p := Params{PageSize: 2, Key: "q"}
+	for {
+		page, err := Paginate(items, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, page.Items...)
+		if page.NextPageToken == "" {
+			break
+		}
+		p.PageToken = page.NextPageToken
+	}
+	if !slices.Equal(got, items) {
+		t.Errorf("pages held %v, want %v", got, items)
+	}
+}
+
+func TestPaginateRejects(t *testing.T) {
+	tampered := []byte(encode(2, "q"))
+	tampered[0]++
+	tests := []struct {
+		name    string
+		params  Params
+		wantErr error
+	}{
+		{"negative page size", Params{PageSize: -1}, ErrInvalidSize},
+		{"negative max results", Params{MaxResults: -1}, ErrInvalidSize},
+		{"token for another query", Params{PageToken: encode(2, "other"), Key: "q"}, ErrInvalidToken},
+		{"token without a key", Params{PageToken: encode(2, "q")}, ErrInvalidToken},
+		{"tampered token", Params{PageToken: string(tampered), Key: "q"}, ErrInvalidToken},
+		{"garbage token", Params{PageToken: "not a token!", Key: "q"}, ErrInvalidToken},
+		{"token without a hash", Params{PageToken: base64.RawURLEncoding.EncodeToString([]byte("2")), Key: "q"}, ErrInvalidToken},
+		{"negative offset", Params{PageToken: encode(-1, "q"), Key: "q"}, ErrInvalidToken},
+		{"offset past total", Params{PageToken: encode(8, "q"), Key: "q"}, ErrInvalidToken},
+		{"offset past max results", Params{MaxResults: 3, PageToken: encode(4, "q"), Key: "q"}, ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := Paginate(items, tt.params)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("got %v with error %v, want %v", page.Items, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -23,6 +23,11 @@ const (
 	OrderManagement_GetOrderServerStream_FullMethodName = "/OrderManagement/GetOrderServerStream"
 	OrderManagement_GetOrderClientStream_FullMethodName = "/OrderManagement/GetOrderClientStream"
 	OrderManagement_GetOrderBiDiStream_FullMethodName   = "/OrderManagement/GetOrderBiDiStream"
+	OrderManagement_AddOrder_FullMethodName             = "/OrderManagement/AddOrder"
+	OrderManagement_UpdateOrder_FullMethodName          = "/OrderManagement/UpdateOrder"
+	OrderManagement_DeleteOrder_FullMethodName          = "/OrderManagement/DeleteOrder"
+	OrderManagement_GetOrderByID_FullMethodName         = "/OrderManagement/GetOrderByID"
+	OrderManagement_WatchOrders_FullMethodName          = "/OrderManagement/WatchOrders"
 )
 
 // OrderManagementClient is the client API for OrderManagement service.
@@ -33,6 +38,11 @@ type OrderManagementClient interface {
 	GetOrderServerStream(ctx context.Context, in *Request, opts ...grpc.CallOption) (OrderManagement_GetOrderServerStreamClient, error)
 	GetOrderClientStream(ctx context.Context, opts ...grpc.CallOption) (OrderManagement_GetOrderClientStreamClient, error)
 	GetOrderBiDiStream(ctx context.Context, opts ...grpc.CallOption) (OrderManagement_GetOrderBiDiStreamClient, error)
+	AddOrder(ctx context.Context, in *Order, opts ...grpc.CallOption) (*Order, error)
+	UpdateOrder(ctx context.Context, in *Order, opts ...grpc.CallOption) (*Order, error)
+	DeleteOrder(ctx context.Context, in *OrderID, opts ...grpc.CallOption) (*Order, error)
+	GetOrderByID(ctx context.Context, in *OrderID, opts ...grpc.CallOption) (*Order, error)
+	WatchOrders(ctx context.Context, in *Request, opts ...grpc.CallOption) (OrderManagement_WatchOrdersClient, error)
 }
 
 type orderManagementClient struct {
@@ -149,6 +159,74 @@ func (x *orderManagementGetOrderBiDiStreamClient) Recv() (*Response, error) {
 	return m, nil
 }
 
+func (c *orderManagementClient) AddOrder(ctx context.Context, in *Order, opts ...grpc.CallOption) (*Order, error) {
+	out := new(Order)
+	err := c.cc.Invoke(ctx, OrderManagement_AddOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderManagementClient) UpdateOrder(ctx context.Context, in *Order, opts ...grpc.CallOption) (*Order, error) {
+	out := new(Order)
+	err := c.cc.Invoke(ctx, OrderManagement_UpdateOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderManagementClient) DeleteOrder(ctx context.Context, in *OrderID, opts ...grpc.CallOption) (*Order, error) {
+	out := new(Order)
+	err := c.cc.Invoke(ctx, OrderManagement_DeleteOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderManagementClient) GetOrderByID(ctx context.Context, in *OrderID, opts ...grpc.CallOption) (*Order, error) {
+	out := new(Order)
+	err := c.cc.Invoke(ctx, OrderManagement_GetOrderByID_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderManagementClient) WatchOrders(ctx context.Context, in *Request, opts ...grpc.CallOption) (OrderManagement_WatchOrdersClient, error) {
+	stream, err := c.cc.NewStream(ctx, &OrderManagement_ServiceDesc.Streams[3], OrderManagement_WatchOrders_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &orderManagementWatchOrdersClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type OrderManagement_WatchOrdersClient interface {
+	Recv() (*OrderEvent, error)
+	grpc.ClientStream
+}
+
+type orderManagementWatchOrdersClient struct {
+	grpc.ClientStream
+}
+
+func (x *orderManagementWatchOrdersClient) Recv() (*OrderEvent, error) {
+	m := new(OrderEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // OrderManagementServer is the server API for OrderManagement service.
 // All implementations must embed UnimplementedOrderManagementServer
 // for forward compatibility
@@ -157,6 +235,11 @@ type OrderManagementServer interface {
 	GetOrderServerStream(*Request, OrderManagement_GetOrderServerStreamServer) error
 	GetOrderClientStream(OrderManagement_GetOrderClientStreamServer) error
 	GetOrderBiDiStream(OrderManagement_GetOrderBiDiStreamServer) error
+	AddOrder(context.Context, *Order) (*Order, error)
+	UpdateOrder(context.Context, *Order) (*Order, error)
+	DeleteOrder(context.Context, *OrderID) (*Order, error)
+	GetOrderByID(context.Context, *OrderID) (*Order, error)
+	WatchOrders(*Request, OrderManagement_WatchOrdersServer) error
 	mustEmbedUnimplementedOrderManagementServer()
 }
 
@@ -176,6 +259,21 @@ func (UnimplementedOrderManagementServer) GetOrderClientStream(OrderManagement_G
 func (UnimplementedOrderManagementServer) GetOrderBiDiStream(OrderManagement_GetOrderBiDiStreamServer) error {
 	return status.Errorf(codes.Unimplemented, "method GetOrderBiDiStream not implemented")
 }
+func (UnimplementedOrderManagementServer) AddOrder(context.Context, *Order) (*Order, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddOrder not implemented")
+}
+func (UnimplementedOrderManagementServer) UpdateOrder(context.Context, *Order) (*Order, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateOrder not implemented")
+}
+func (UnimplementedOrderManagementServer) DeleteOrder(context.Context, *OrderID) (*Order, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteOrder not implemented")
+}
+func (UnimplementedOrderManagementServer) GetOrderByID(context.Context, *OrderID) (*Order, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrderByID not implemented")
+}
+func (UnimplementedOrderManagementServer) WatchOrders(*Request, OrderManagement_WatchOrdersServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchOrders not implemented")
+}
 func (UnimplementedOrderManagementServer) mustEmbedUnimplementedOrderManagementServer() {}
 
 // UnsafeOrderManagementServer may be embedded to opt out of forward compatibility for this service.
@@ -280,6 +378,99 @@ func (x *orderManagementGetOrderBiDiStreamServer) Recv() (*Request, error) {
 	return m, nil
 }
 
+func _OrderManagement_AddOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Order)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderManagementServer).AddOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderManagement_AddOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderManagementServer).AddOrder(ctx, req.(*Order))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderManagement_UpdateOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Order)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderManagementServer).UpdateOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderManagement_UpdateOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderManagementServer).UpdateOrder(ctx, req.(*Order))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderManagement_DeleteOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderManagementServer).DeleteOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderManagement_DeleteOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderManagementServer).DeleteOrder(ctx, req.(*OrderID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderManagement_GetOrderByID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderManagementServer).GetOrderByID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderManagement_GetOrderByID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderManagementServer).GetOrderByID(ctx, req.(*OrderID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderManagement_WatchOrders_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Request)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrderManagementServer).WatchOrders(m, &orderManagementWatchOrdersServer{stream})
+}
+
+type OrderManagement_WatchOrdersServer interface {
+	Send(*OrderEvent) error
+	grpc.ServerStream
+}
+
+type orderManagementWatchOrdersServer struct {
+	grpc.ServerStream
+}
+
+func (x *orderManagementWatchOrdersServer) Send(m *OrderEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // OrderManagement_ServiceDesc is the grpc.ServiceDesc for OrderManagement service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -291,6 +482,22 @@ var OrderManagement_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetOrderUnary",
 			Handler:    _OrderManagement_GetOrderUnary_Handler,
 		},
+		{
+			MethodName: "AddOrder",
+			Handler:    _OrderManagement_AddOrder_Handler,
+		},
+		{
+			MethodName: "UpdateOrder",
+			Handler:    _OrderManagement_UpdateOrder_Handler,
+		},
+		{
+			MethodName: "DeleteOrder",
+			Handler:    _OrderManagement_DeleteOrder_Handler,
+		},
+		{
+			MethodName: "GetOrderByID",
+			Handler:    _OrderManagement_GetOrderByID_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -309,6 +516,11 @@ var OrderManagement_ServiceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "WatchOrders",
+			Handler:       _OrderManagement_WatchOrders_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "proto/order_management.proto",
 }
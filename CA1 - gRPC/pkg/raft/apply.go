@@ -0,0 +1,117 @@
+package raft
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/charmbracelet/log"
+
+	"dist-grpc/pkg/catalog"
+	pb "dist-grpc/pkg/proto"
+)
+
+// applyLoop applies the committed entries to the catalog in log order.
+func (n *Node) applyLoop(ctx context.Context) {
+	for {
+		n.mu.Lock()
+		pending := n.lastApplied < n.commitIndex
+		changed := n.changed
+		n.mu.Unlock()
+		if pending {
+			n.applyCommitted()
+			n.maybeSnapshot()
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-changed:
+		}
+	}
+}
+
+func (n *Node) applyCommitted() {
+	n.applyMu.Lock()
+	defer n.applyMu.Unlock()
+	n.mu.Lock()
+	entries := n.log.between(n.lastApplied+1, n.commitIndex, maxBatch)
+	n.mu.Unlock()
+
+	for _, entry := range entries {
+		var err error
+		if entry.GetType() == pb.EntryType_ENTRY_TYPE_COMMAND {
+			// Failures such as adding an order twice happen alike on every
+			// member, and only go back to the writer.
+			err = applyCommand(n.store, entry)
+		}
+		n.mu.Lock()
+		n.lastApplied = entry.GetIndex()
+		if p, ok := n.proposals[entry.GetIndex()]; ok {
+			if p.term != entry.GetTerm() {
+				err = errLeadershipLost
+			}
+			p.done <- err
+			delete(n.proposals, entry.GetIndex())
+		}
+		n.mu.Unlock()
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.broadcast()
+}
+
+// maybeSnapshot compacts the applied entries into a snapshot of the catalog
+// once there are enough of them. The catalog is as of the last applied entry
+// under applyMu, and the saved log does not change under appendMu, so that
+// the snapshot is saved out of mu.
+func (n *Node) maybeSnapshot() {
+	n.appendMu.Lock()
+	defer n.appendMu.Unlock()
+	n.applyMu.Lock()
+	defer n.applyMu.Unlock()
+	n.mu.Lock()
+	base, _ := n.log.base()
+	applied := n.lastApplied
+	if applied-base < uint64(n.config.SnapshotThreshold) {
+		n.mu.Unlock()
+		return
+	}
+	term, _ := n.log.termAt(applied)
+	members, _ := n.log.membersAt(applied)
+	rest := slices.Clone(n.log.between(applied+1, n.saved, math.MaxInt))
+	n.mu.Unlock()
+
+	snapshot := n.snapshotOf(applied, term, members)
+	if err := n.saveSnapshot(snapshot, rest); err != nil {
+		log.Error("Failed to take snapshot", "err", err)
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.log.compact(snapshot)
+	n.saved = max(n.saved, applied)
+	log.Debug("Took snapshot", "index", applied, "entries", len(n.log.entries))
+}
+
+func applyCommand(store catalog.Store, entry *pb.RaftEntry) error {
+	order := catalog.FromProto(entry.GetOrder())
+	switch entry.GetOp() {
+	case pb.Operation_OPERATION_ADD:
+		return store.Add(order)
+	case pb.Operation_OPERATION_UPDATE:
+		return store.Update(order)
+	case pb.Operation_OPERATION_DELETE:
+		return store.Delete(order.ID)
+	}
+	return fmt.Errorf("unknown operation %s", entry.GetOp())
+}
+
+func replaceCatalog(store catalog.Store, snapshot *pb.RaftSnapshot) error {
+	if err := catalog.Replace(store, ordersOf(snapshot)); err != nil {
+		return fmt.Errorf("failed to install snapshot: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package raft
+
+import (
+	"context"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "dist-grpc/pkg/proto"
+)
+
+// campaign stands for election in the next term, asking every other member
+// for its vote. It is called with mu held.
+func (n *Node) campaign(ctx context.Context) {
+	n.resetElection()
+	if err := n.saveTerm(n.term+1, n.id); err != nil {
+		log.Error("Not standing for election", "err", err)
+		return
+	}
+	n.state, n.leader = Candidate, ""
+	n.broadcast()
+	term := n.term
+	lastIndex, lastTerm := n.log.last()
+	log.Info("Standing for election", "term", term)
+
+	votes := 1
+	if votes >= n.quorum() {
+		n.becomeLeader()
+		return
+	}
+	req := &pb.VoteRequest{Term: term, Candidate: n.id, LastIndex: lastIndex, LastTerm: lastTerm}
+	for _, m := range n.members {
+		if m.GetId() == n.id {
+			continue
+		}
+		go func() {
+			client, err := n.client(m.GetAddress())
+			if err != nil {
+				return
+			}
+			callCtx, cancel := context.WithTimeout(ctx, n.config.ElectionTimeout)
+			defer cancel()
+			resp, err := client.RequestVote(callCtx, req)
+			if err != nil {
+				return
+			}
+			n.mu.Lock()
+			defer n.mu.Unlock()
+			if resp.GetTerm() > n.term {
+				if err := n.stepDown(resp.GetTerm()); err != nil {
+					log.Error("Failed to step down", "err", err)
+				}
+				return
+			}
+			if n.state != Candidate || n.term != term || !resp.GetGranted() {
+				return
+			}
+			votes++
+			if votes >= n.quorum() {
+				n.becomeLeader()
+			}
+		}()
+	}
+}
+
+func (n *Node) RequestVote(ctx context.Context, req *pb.VoteRequest) (*pb.VoteResponse, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	// Members that still hear from a leader ignore candidates, so that a
+	// server cut off or removed from the cluster does not disrupt it.
+	if n.state == Leader || (n.leader != "" && time.Since(n.lastContact) < n.config.ElectionTimeout) {
+		return &pb.VoteResponse{Term: n.term}, nil
+	}
+	if req.GetTerm() < n.term {
+		return &pb.VoteResponse{Term: n.term}, nil
+	}
+	if req.GetTerm() > n.term {
+		if err := n.stepDown(req.GetTerm()); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+	lastIndex, lastTerm := n.log.last()
+	upToDate := req.GetLastTerm() > lastTerm || (req.GetLastTerm() == lastTerm && req.GetLastIndex() >= lastIndex)
+	if !upToDate || (n.votedFor != "" && n.votedFor != req.GetCandidate()) {
+		return &pb.VoteResponse{Term: n.term}, nil
+	}
+	// A vote that could not be saved is refused, as the node could vote
+	// again in the term after a restart.
+	if err := n.saveTerm(n.term, req.GetCandidate()); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	n.resetElection()
+	log.Debug("Granted vote", "candidate", req.GetCandidate(), "term", n.term)
+	return &pb.VoteResponse{Term: n.term, Granted: true}, nil
+}
+
+// becomeLeader starts replicating the log to the other members, after an
+// empty entry of the new term that commits the entries of former terms with
+// it. It is called with mu held.
+func (n *Node) becomeLeader() {
+	n.state, n.leader = Leader, n.id
+	n.lastContact = time.Now()
+	ctx, cancel := context.WithCancel(context.Background())
+	n.stop = cancel
+	n.peers = make(map[string]*peer)
+	log.Info("Leading the cluster", "term", n.term, "members", memberIDs(n.members))
+	n.appendEntry(&pb.RaftEntry{Type: pb.EntryType_ENTRY_TYPE_NOOP})
+	go n.persist(ctx)
+	n.startPeers(ctx)
+}
+
+// checkQuorum steps down a leader that has not heard from a majority for an
+// election timeout, as the others have likely elected another leader by
+// then. It is called with mu held.
+func (n *Node) checkQuorum() {
+	acked := 0
+	if n.isMember(n.id) {
+		acked++
+	}
+	for _, p := range n.peers {
+		if time.Since(p.lastAck) < n.config.ElectionTimeout {
+			acked++
+		}
+	}
+	if acked >= n.quorum() {
+		n.lastContact = time.Now()
+		return
+	}
+	if time.Since(n.lastContact) >= n.config.ElectionTimeout {
+		log.Warn("Lost contact with the majority")
+		_ = n.stepDown(n.term)
+	}
+}
@@ -0,0 +1,91 @@
+package raft
+
+import pb "dist-grpc/pkg/proto"
+
+// raftLog holds the entries that follow the snapshot, which covers every
+// entry up to its index.
+type raftLog struct {
+	snapshot *pb.RaftSnapshot
+	entries  []*pb.RaftEntry
+}
+
+func (l *raftLog) base() (index, term uint64) {
+	return l.snapshot.GetIndex(), l.snapshot.GetTerm()
+}
+
+func (l *raftLog) last() (index, term uint64) {
+	if len(l.entries) == 0 {
+		return l.base()
+	}
+	e := l.entries[len(l.entries)-1]
+	return e.GetIndex(), e.GetTerm()
+}
+
+// termAt returns the term of the entry at index, if the log still knows it.
+func (l *raftLog) termAt(index uint64) (uint64, bool) {
+	base, baseTerm := l.base()
+	last, _ := l.last()
+	switch {
+	case index == base:
+		return baseTerm, true
+	case index < base || index > last:
+		return 0, false
+	}
+	return l.entries[index-base-1].GetTerm(), true
+}
+
+// between returns the entries from first to last included, at most max of
+// them. first must follow the snapshot.
+func (l *raftLog) between(first, last uint64, max int) []*pb.RaftEntry {
+	base, _ := l.base()
+	if end, _ := l.last(); last > end {
+		last = end
+	}
+	if first > last {
+		return nil
+	}
+	if last-first+1 > uint64(max) {
+		last = first + uint64(max) - 1
+	}
+	return l.entries[first-base-1 : last-base]
+}
+
+func (l *raftLog) append(entries ...*pb.RaftEntry) {
+	l.entries = append(l.entries, entries...)
+}
+
+// truncate drops the entries from index on.
+func (l *raftLog) truncate(index uint64) {
+	base, _ := l.base()
+	l.entries = l.entries[:index-base-1]
+}
+
+// compact replaces the entries up to the snapshot with it, keeping those
+// that follow it when the log agrees with the snapshot on its last entry.
+func (l *raftLog) compact(snapshot *pb.RaftSnapshot) {
+	term, ok := l.termAt(snapshot.GetIndex())
+	var rest []*pb.RaftEntry
+	if ok && term == snapshot.GetTerm() {
+		base, _ := l.base()
+		rest = append(rest, l.entries[snapshot.GetIndex()-base:]...)
+	}
+	l.snapshot, l.entries = snapshot, rest
+}
+
+// membersAt returns the membership in effect at index, set by the last
+// MEMBERS entry up to it or else by the snapshot.
+func (l *raftLog) membersAt(index uint64) (members []*pb.Member, configIndex uint64) {
+	base, _ := l.base()
+	for i := len(l.entries) - 1; i >= 0; i-- {
+		e := l.entries[i]
+		if e.GetIndex() <= index && e.GetType() == pb.EntryType_ENTRY_TYPE_MEMBERS {
+			return e.GetMembers(), e.GetIndex()
+		}
+	}
+	return l.snapshot.GetMembers(), base
+}
+
+func (l *raftLog) members() ([]*pb.Member, uint64) {
+	last, _ := l.last()
+	return l.membersAt(last)
+}
@@ -0,0 +1,332 @@
+package raft
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"dist-grpc/pkg/catalog"
+	pb "dist-grpc/pkg/proto"
+)
+
+// errLeadershipLost fails the writes of a leader that stepped down before
+// they were committed. The next leader may still commit them.
+var errLeadershipLost = fmt.Errorf("%w: leadership lost before the write was committed, it may still be applied", catalog.ErrNotReplicated)
+
+// proposal is a write of the leader waiting to be applied.
+type proposal struct {
+	term uint64
+	done chan error
+}
+
+// errNotLeader is returned to the calls forwarded to a member that is no
+// longer the leader, which the caller retries once it knows the new one.
+var errNotLeader = status.Error(codes.FailedPrecondition, "not the leader")
+
+// toStatus carries a catalog error over the Raft service.
+func toStatus(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, catalog.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, catalog.ErrExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, catalog.ErrNotReplicated), errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// fromStatus turns the error of a forwarded write back into a catalog error.
+func fromStatus(err error) error {
+	switch status.Code(err) {
+	case codes.OK:
+		return nil
+	case codes.NotFound:
+		return catalog.ErrNotFound
+	case codes.AlreadyExists:
+		return catalog.ErrExists
+	}
+	return fmt.Errorf("%w: %s", catalog.ErrNotReplicated, status.Convert(err).Message())
+}
+
+// leaderClient waits until a leader is known, returning a client for it, or
+// nil when the node is the leader itself.
+func (n *Node) leaderClient(ctx context.Context) (pb.RaftClient, error) {
+	for {
+		n.mu.Lock()
+		state, addr := n.state, n.addressOf(n.leader)
+		changed := n.changed
+		n.mu.Unlock()
+		if state == Leader {
+			return nil, nil
+		}
+		if addr != "" {
+			return n.client(addr)
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: no leader elected", catalog.ErrNotReplicated)
+		}
+	}
+}
+
+// forward calls the leader until a member acting as leader answers, or
+// handles the call itself when it is the leader.
+func (n *Node) forward(ctx context.Context, local func() error, remote func(pb.RaftClient) error) error {
+	for {
+		client, err := n.leaderClient(ctx)
+		if err != nil {
+			return err
+		}
+		if client == nil {
+			err = local()
+		} else {
+			err = remote(client)
+		}
+		if status.Code(err) != codes.FailedPrecondition || ctx.Err() != nil {
+			return err
+		}
+		// The leader stepped down meanwhile, wait to hear of the next.
+		n.mu.Lock()
+		changed := n.changed
+		n.mu.Unlock()
+		select {
+		case <-changed:
+		case <-ctx.Done():
+		}
+	}
+}
+
+// propose has the leader commit the write and waits for it to be applied,
+// returning the error the catalog returned.
+func (n *Node) propose(op pb.Operation, order catalog.Order) error {
+	ctx, cancel := context.WithTimeout(context.Background(), n.config.ProposeTimeout)
+	defer cancel()
+	req := &pb.ProposeRequest{Op: op, Order: catalog.ToProto(order)}
+	var applyErr error
+	err := n.forward(ctx, func() error {
+		applyErr = n.proposeLocal(ctx, req)
+		return toStatus(applyErr)
+	}, func(client pb.RaftClient) error {
+		_, err := client.Propose(ctx, req)
+		applyErr = fromStatus(err)
+		return err
+	})
+	if status.Code(err) == codes.FailedPrecondition {
+		return fmt.Errorf("%w: no leader elected", catalog.ErrNotReplicated)
+	}
+	return applyErr
+}
+
+func (n *Node) proposeLocal(ctx context.Context, req *pb.ProposeRequest) error {
+	n.mu.Lock()
+	if n.state != Leader {
+		n.mu.Unlock()
+		return errNotLeader
+	}
+	p := &proposal{term: n.term, done: make(chan error, 1)}
+	index := n.appendEntry(&pb.RaftEntry{Type: pb.EntryType_ENTRY_TYPE_COMMAND, Op: req.GetOp(), Order: req.GetOrder()})
+	n.proposals[index] = p
+	n.mu.Unlock()
+
+	select {
+	case err := <-p.done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("%w: not committed in time, it may still be applied", catalog.ErrNotReplicated)
+	}
+}
+
+func (n *Node) Propose(ctx context.Context, req *pb.ProposeRequest) (*pb.ProposeResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, n.config.ProposeTimeout)
+	defer cancel()
+	if err := n.proposeLocal(ctx, req); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.ProposeResponse{}, nil
+}
+
+// Barrier waits until the catalog holds every write committed before the
+// call, which the leader confirms.
+func (n *Node) Barrier(ctx context.Context) error {
+	var index uint64
+	err := n.forward(ctx, func() error {
+		var err error
+		index, err = n.readIndex(ctx)
+		return err
+	}, func(client pb.RaftClient) error {
+		resp, err := client.ReadIndex(ctx, &pb.ReadIndexRequest{})
+		index = resp.GetIndex()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	for {
+		n.mu.Lock()
+		applied, changed := n.lastApplied, n.changed
+		n.mu.Unlock()
+		if applied >= index {
+			return nil
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// readIndex returns the commit index of the leader once a majority has
+// confirmed it still leads, so that no other leader can have committed
+// writes it does not know of.
+func (n *Node) readIndex(ctx context.Context) (uint64, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	// The commit index is only known to be current once an entry of the
+	// term is committed.
+	for {
+		if n.state != Leader {
+			return 0, errNotLeader
+		}
+		if term, _ := n.log.termAt(n.commitIndex); term == n.term {
+			break
+		}
+		if err := n.wait(ctx); err != nil {
+			return 0, err
+		}
+	}
+	index, term := n.commitIndex, n.term
+	n.round++
+	round := n.round
+	n.broadcast()
+	for {
+		if n.state != Leader || n.term != term {
+			return 0, errNotLeader
+		}
+		acked := 1
+		for _, p := range n.peers {
+			if p.ackRound >= round {
+				acked++
+			}
+		}
+		if acked >= n.quorum() {
+			return index, nil
+		}
+		if err := n.wait(ctx); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// wait releases mu until the next change. It is called with mu held.
+func (n *Node) wait(ctx context.Context) error {
+	changed := n.changed
+	n.mu.Unlock()
+	defer n.mu.Lock()
+	select {
+	case <-changed:
+		return nil
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	}
+}
+
+func (n *Node) ReadIndex(ctx context.Context, req *pb.ReadIndexRequest) (*pb.ReadIndexResponse, error) {
+	index, err := n.readIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ReadIndexResponse{Index: index}, nil
+}
+
+func (n *Node) AddMember(ctx context.Context, req *pb.MembershipRequest) (*pb.RaftStatus, error) {
+	m := req.GetMember()
+	if m.GetId() == "" || m.GetAddress() == "" {
+		return nil, status.Error(codes.InvalidArgument, "member id and address are required")
+	}
+	return n.changeMembers(ctx, req, func(members []*pb.Member) ([]*pb.Member, error) {
+		for _, other := range members {
+			if other.GetId() == m.GetId() {
+				return nil, status.Errorf(codes.AlreadyExists, "%s is already a member", m.GetId())
+			}
+		}
+		return append(slices.Clone(members), m), nil
+	}, pb.RaftClient.AddMember)
+}
+
+func (n *Node) RemoveMember(ctx context.Context, req *pb.MembershipRequest) (*pb.RaftStatus, error) {
+	id := req.GetMember().GetId()
+	return n.changeMembers(ctx, req, func(members []*pb.Member) ([]*pb.Member, error) {
+		i := slices.IndexFunc(members, func(m *pb.Member) bool { return m.GetId() == id })
+		if i < 0 {
+			return nil, status.Errorf(codes.NotFound, "%s is not a member", id)
+		}
+		if len(members) == 1 {
+			return nil, status.Error(codes.FailedPrecondition, "the last member cannot be removed")
+		}
+		return slices.Delete(slices.Clone(members), i, i+1), nil
+	}, pb.RaftClient.RemoveMember)
+}
+
+// changeMembers has the leader append the membership that change makes of
+// the current one, one change at a time, and waits for it to be committed.
+func (n *Node) changeMembers(ctx context.Context, req *pb.MembershipRequest,
+	change func([]*pb.Member) ([]*pb.Member, error),
+	call func(pb.RaftClient, context.Context, *pb.MembershipRequest, ...grpc.CallOption) (*pb.RaftStatus, error),
+) (*pb.RaftStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, n.config.ProposeTimeout)
+	defer cancel()
+	var res *pb.RaftStatus
+	err := n.forward(ctx, func() error {
+		n.mu.Lock()
+		if n.state != Leader {
+			n.mu.Unlock()
+			return errNotLeader
+		}
+		if n.configIndex > n.commitIndex {
+			n.mu.Unlock()
+			return status.Error(codes.Aborted, "another membership change is in progress")
+		}
+		members, err := change(n.members)
+		if err != nil {
+			n.mu.Unlock()
+			return err
+		}
+		p := &proposal{term: n.term, done: make(chan error, 1)}
+		index := n.appendEntry(&pb.RaftEntry{Type: pb.EntryType_ENTRY_TYPE_MEMBERS, Members: members})
+		n.proposals[index] = p
+		n.mu.Unlock()
+		select {
+		case err = <-p.done:
+		case <-ctx.Done():
+			err = fmt.Errorf("%w: membership change not committed in time", catalog.ErrNotReplicated)
+		}
+		if err != nil {
+			return toStatus(err)
+		}
+		n.mu.Lock()
+		res = n.status()
+		n.mu.Unlock()
+		return nil
+	}, func(client pb.RaftClient) error {
+		var err error
+		res, err = call(client, ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
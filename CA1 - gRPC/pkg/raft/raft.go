@@ -0,0 +1,376 @@
+// Package raft replicates an order catalog over a cluster of servers with
+// the Raft consensus algorithm. A leader is elected by the majority, appends
+// every write to its log and commits it once a majority has it, after which
+// every server applies it to its catalog in log order. Servers forward their
+// writes and the membership changes to the leader, and confirm linearizable
+// reads with it. Applied entries are compacted into snapshots of the catalog,
+// which also bring lagging servers up to date.
+package raft
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"dist-grpc/pkg/catalog"
+	pb "dist-grpc/pkg/proto"
+)
+
+type State string
+
+const (
+	Follower  State = "follower"
+	Candidate State = "candidate"
+	Leader    State = "leader"
+)
+
+const (
+	DefaultElectionTimeout   = time.Second
+	DefaultHeartbeat         = 100 * time.Millisecond
+	DefaultSnapshotThreshold = 1024
+	DefaultProposeTimeout    = 5 * time.Second
+	// maxBatch is how many entries an AppendEntries call carries at most.
+	maxBatch = 256
+)
+
+type Config struct {
+	// ID names the node among the members of the cluster.
+	ID string
+	// ElectionTimeout is how long a follower waits to hear from a leader
+	// before standing for election, randomized up to twice as long.
+	ElectionTimeout time.Duration
+	// Heartbeat is how often the leader appends nothing to the followers it
+	// has nothing to send.
+	Heartbeat time.Duration
+	// SnapshotThreshold is how many entries are applied between snapshots.
+	SnapshotThreshold int
+	// ProposeTimeout is how long a write may wait for a leader to commit it.
+	ProposeTimeout time.Duration
+	Storage        Storage
+	Dial           func(addr string) (pb.RaftClient, error)
+}
+
+// Node is a member of a Raft cluster. It is the catalog.Store a server
+// should serve, and the Raft service the other members call.
+type Node struct {
+	pb.UnimplementedRaftServer
+	id     string
+	store  catalog.Store
+	config Config
+	rand   *rand.Rand
+
+	// appendMu serializes the saving of the log, which happens outside mu,
+	// with the changes to the entries already saved. It is taken before
+	// applyMu and mu.
+	appendMu sync.Mutex
+	// applyMu serializes the changes to the catalog, which the applier and
+	// the installation of snapshots make.
+	applyMu sync.Mutex
+
+	mu          sync.Mutex
+	state       State
+	term        uint64
+	votedFor    string
+	leader      string
+	log         raftLog
+	members     []*pb.Member
+	configIndex uint64
+	commitIndex uint64
+	lastApplied uint64
+	// saved is the last entry of the log in storage. The leader saves the
+	// entries it appends in the background, and only counts itself as
+	// having those up to saved.
+	saved uint64
+	// lastContact is when the node last heard from a leader, or, for the
+	// leader, from a majority.
+	lastContact      time.Time
+	electionDeadline time.Time
+	// Leader state: the progress of each follower, the writes waiting to be
+	// applied and the rounds of heartbeats confirming the leadership.
+	peers     map[string]*peer
+	proposals map[uint64]*proposal
+	round     uint64
+	stop      context.CancelFunc
+	// changed is closed and replaced on every change other goroutines may
+	// wait for.
+	changed chan struct{}
+	clients map[string]pb.RaftClient
+	cancel  context.CancelFunc
+}
+
+// New returns a node serving the store, which holds the catalog the cluster
+// started from. members is the membership the cluster starts with, the same
+// on every initial member, and is empty on a node joining a running cluster.
+// A node restarted from its storage replaces the catalog with its snapshot
+// and replays its log from there.
+func New(store catalog.Store, members []*pb.Member, config Config) (*Node, error) {
+	if config.ElectionTimeout <= 0 {
+		config.ElectionTimeout = DefaultElectionTimeout
+	}
+	if config.Heartbeat <= 0 {
+		config.Heartbeat = DefaultHeartbeat
+	}
+	if config.SnapshotThreshold <= 0 {
+		config.SnapshotThreshold = DefaultSnapshotThreshold
+	}
+	if config.ProposeTimeout <= 0 {
+		config.ProposeTimeout = DefaultProposeTimeout
+	}
+	if config.Storage == nil {
+		config.Storage = NewMemoryStorage()
+	}
+	n := &Node{
+		id:        config.ID,
+		store:     store,
+		config:    config,
+		rand:      rand.New(rand.NewSource(time.Now().UnixNano())),
+		state:     Follower,
+		proposals: make(map[uint64]*proposal),
+		changed:   make(chan struct{}),
+		clients:   make(map[string]pb.RaftClient),
+	}
+
+	saved, err := config.Storage.Load()
+	if err != nil {
+		return nil, err
+	}
+	if saved == nil {
+		n.log.snapshot = n.snapshotOf(0, 0, members)
+		if err := n.saveSnapshot(n.log.snapshot, nil); err != nil {
+			return nil, err
+		}
+	} else {
+		n.term, n.votedFor = saved.GetTerm(), saved.GetVotedFor()
+		n.log = raftLog{snapshot: saved.GetSnapshot(), entries: saved.GetEntries()}
+		if err := catalog.Replace(store, ordersOf(saved.GetSnapshot())); err != nil {
+			return nil, fmt.Errorf("failed to restore snapshot: %w", err)
+		}
+		log.Info("Restored raft state", "term", n.term, "snapshot", n.log.snapshot.GetIndex(), "entries", len(n.log.entries))
+	}
+	n.saved, _ = n.log.last()
+	n.commitIndex, _ = n.log.base()
+	n.lastApplied = n.commitIndex
+	n.members, n.configIndex = n.log.members()
+	n.resetElection()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	n.cancel = cancel
+	go n.tick(ctx)
+	go n.applyLoop(ctx)
+	return n, nil
+}
+
+// Close stops the node, which no longer takes part in the cluster.
+func (n *Node) Close() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.cancel()
+	n.stopLeading()
+	n.state = Follower
+}
+
+func (n *Node) snapshotOf(index, term uint64, members []*pb.Member) *pb.RaftSnapshot {
+	snapshot := &pb.RaftSnapshot{Index: index, Term: term, Members: members}
+	for _, order := range n.store.List() {
+		snapshot.Orders = append(snapshot.Orders, catalog.ToProto(order))
+	}
+	return snapshot
+}
+
+func ordersOf(snapshot *pb.RaftSnapshot) []catalog.Order {
+	orders := make([]catalog.Order, 0, len(snapshot.GetOrders()))
+	for _, order := range snapshot.GetOrders() {
+		orders = append(orders, catalog.FromProto(order))
+	}
+	return orders
+}
+
+// saveTerm saves a new term or vote, and only then takes it, as the node
+// must not forget a vote it cast. It is called with mu held.
+func (n *Node) saveTerm(term uint64, votedFor string) error {
+	if err := n.config.Storage.SaveTerm(term, votedFor); err != nil {
+		return fmt.Errorf("failed to save raft term: %w", err)
+	}
+	n.term, n.votedFor = term, votedFor
+	return nil
+}
+
+// saveEntries saves entries about to be appended to the log, which replace
+// the saved ones from the index of the first on. It is called with appendMu
+// held but not mu.
+func (n *Node) saveEntries(entries []*pb.RaftEntry) error {
+	if err := n.config.Storage.Append(entries); err != nil {
+		return fmt.Errorf("failed to save raft entries: %w", err)
+	}
+	return nil
+}
+
+// saveSnapshot saves a snapshot about to compact the log, along with the
+// saved entries that follow it.
+func (n *Node) saveSnapshot(snapshot *pb.RaftSnapshot, entries []*pb.RaftEntry) error {
+	if err := n.config.Storage.SaveSnapshot(snapshot, entries); err != nil {
+		return fmt.Errorf("failed to save raft snapshot: %w", err)
+	}
+	return nil
+}
+
+func (n *Node) broadcast() {
+	close(n.changed)
+	n.changed = make(chan struct{})
+}
+
+func (n *Node) resetElection() {
+	timeout := n.config.ElectionTimeout + time.Duration(n.rand.Int63n(int64(n.config.ElectionTimeout)))
+	n.electionDeadline = time.Now().Add(timeout)
+}
+
+func (n *Node) addressOf(id string) string {
+	for _, m := range n.members {
+		if m.GetId() == id {
+			return m.GetAddress()
+		}
+	}
+	return ""
+}
+
+func (n *Node) isMember(id string) bool {
+	return n.addressOf(id) != ""
+}
+
+// quorum is how many members make a majority.
+func (n *Node) quorum() int {
+	return len(n.members)/2 + 1
+}
+
+func (n *Node) client(addr string) (pb.RaftClient, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if c, ok := n.clients[addr]; ok {
+		return c, nil
+	}
+	c, err := n.config.Dial(addr)
+	if err != nil {
+		return nil, err
+	}
+	n.clients[addr] = c
+	return c, nil
+}
+
+// setMembers follows the membership in effect at the end of the log. It is
+// called with mu held after every change of the log.
+func (n *Node) setMembers() {
+	members, index := n.log.members()
+	same := slices.EqualFunc(members, n.members, func(a, b *pb.Member) bool {
+		return a.GetId() == b.GetId() && a.GetAddress() == b.GetAddress()
+	})
+	n.members, n.configIndex = members, index
+	if same {
+		return
+	}
+	log.Info("Membership changed", "members", memberIDs(members), "index", index)
+	if n.state == Leader {
+		n.updatePeers()
+	}
+}
+
+func memberIDs(members []*pb.Member) []string {
+	ids := make([]string, 0, len(members))
+	for _, m := range members {
+		ids = append(ids, m.GetId())
+	}
+	return ids
+}
+
+// stepDown makes the node a follower, in the term when it is newer and
+// could be saved. It is called with mu held.
+func (n *Node) stepDown(term uint64) error {
+	var err error
+	if term > n.term {
+		if err = n.saveTerm(term, ""); err == nil {
+			n.leader = ""
+		}
+	}
+	if n.state == Leader {
+		log.Warn("Stepping down", "term", n.term)
+		n.stopLeading()
+		n.leader = ""
+	}
+	if n.state != Follower {
+		n.state = Follower
+		n.broadcast()
+	}
+	return err
+}
+
+// tick stands for election when no leader was heard from in time, and
+// makes a leader that lost the majority step down.
+func (n *Node) tick(ctx context.Context) {
+	ticker := time.NewTicker(n.config.Heartbeat / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		n.mu.Lock()
+		switch {
+		case n.state == Leader:
+			n.checkQuorum()
+		case time.Now().After(n.electionDeadline) && n.isMember(n.id):
+			n.campaign(ctx)
+		}
+		n.mu.Unlock()
+	}
+}
+
+func (n *Node) Status(ctx context.Context, req *pb.RaftStatusRequest) (*pb.RaftStatus, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.status(), nil
+}
+
+func (n *Node) status() *pb.RaftStatus {
+	last, _ := n.log.last()
+	base, _ := n.log.base()
+	return &pb.RaftStatus{
+		Id:            n.id,
+		State:         string(n.state),
+		Term:          n.term,
+		Leader:        n.leader,
+		CommitIndex:   n.commitIndex,
+		AppliedIndex:  n.lastApplied,
+		LastIndex:     last,
+		SnapshotIndex: base,
+		Members:       n.members,
+	}
+}
+
+func (n *Node) List() []catalog.Order {
+	return n.store.List()
+}
+
+func (n *Node) Get(id string) (catalog.Order, error) {
+	return n.store.Get(id)
+}
+
+func (n *Node) Subscribe(fn func(catalog.Change)) func() {
+	return n.store.Subscribe(fn)
+}
+
+func (n *Node) Add(order catalog.Order) error {
+	return n.propose(pb.Operation_OPERATION_ADD, order)
+}
+
+func (n *Node) Update(order catalog.Order) error {
+	return n.propose(pb.Operation_OPERATION_UPDATE, order)
+}
+
+func (n *Node) Delete(id string) error {
+	return n.propose(pb.Operation_OPERATION_DELETE, catalog.Order{ID: id})
+}
@@ -0,0 +1,379 @@
+package raft_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"dist-grpc/internal/harnesstest"
+	"dist-grpc/pkg/catalog"
+	"dist-grpc/pkg/harness"
+	pb "dist-grpc/pkg/proto"
+	"dist-grpc/pkg/raft"
+)
+
+const (
+	raftElectionTimeout = 150 * time.Millisecond
+	raftHeartbeat       = 30 * time.Millisecond
+	raftProposeTimeout  = 2 * time.Second
+	leaderTimeout       = 3 * time.Second
+	syncTimeout         = 2 * time.Second
+)
+
+var raftIDs = []string{"raft-1", "raft-2", "raft-3"}
+
+// startCluster starts the raftIDs members from the fruits catalog and waits
+// for one of them to lead.
+func startCluster(t *testing.T, snapshotThreshold int) (*harness.Cluster, *harness.Member) {
+	t.Helper()
+	c, err := harness.StartCluster(raftIDs, func() catalog.Store {
+		return harness.NewFakeStore(harness.Fruits...)
+	}, raft.Config{
+		ElectionTimeout:   raftElectionTimeout,
+		Heartbeat:         raftHeartbeat,
+		SnapshotThreshold: snapshotThreshold,
+		ProposeTimeout:    raftProposeTimeout,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(c.Close)
+	leader, err := c.Leader(leaderTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, leader
+}
+
+// followerOf returns a running member other than the leader.
+func followerOf(c *harness.Cluster, leader *harness.Member) *harness.Member {
+	for _, id := range raftIDs {
+		if m := c.Member(id); m != nil && id != leader.ID {
+			return m
+		}
+	}
+	return nil
+}
+
+// waitConverged waits for the running members of ids to hold the same
+// catalog, which holds every name of want.
+func waitConverged(t *testing.T, c *harness.Cluster, ids []string, want ...string) {
+	t.Helper()
+	deadline := time.Now().Add(syncTimeout)
+	for {
+		err := converged(c, ids, want)
+		if err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func converged(c *harness.Cluster, ids []string, want []string) error {
+	var first []string
+	for i, id := range ids {
+		m := c.Member(id)
+		if m == nil {
+			return fmt.Errorf("%s is not running", id)
+		}
+		got := harness.Catalog(m.Harness.Store)
+		if i == 0 {
+			first = got
+		} else if !slices.Equal(got, first) {
+			return fmt.Errorf("%s holds %v, %s holds %v", id, got, ids[0], first)
+		}
+		names := make(map[string]bool)
+		for _, order := range m.Harness.Store.List() {
+			names[order.Name] = true
+		}
+		for _, name := range want {
+			if !names[name] {
+				return fmt.Errorf("%s misses %q", id, name)
+			}
+		}
+	}
+	return nil
+}
+
+func raftStatus(t *testing.T, m *harness.Member) *pb.RaftStatus {
+	t.Helper()
+	st, err := m.Node.Status(context.Background(), &pb.RaftStatusRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return st
+}
+
+func addOrder(t *testing.T, h *harness.Harness, name string) {
+	t.Helper()
+	if _, err := h.AddOrder(name); err != nil {
+		t.Fatalf("add %q: %v", name, err)
+	}
+}
+
+// TestElection checks that the members elect a single leader, which every
+// other member follows, and serve the catalog they started from.
+func TestElection(t *testing.T) {
+	c, leader := startCluster(t, 0)
+
+	deadline := time.Now().Add(syncTimeout)
+	for _, id := range raftIDs {
+		for {
+			st := raftStatus(t, c.Member(id))
+			if st.GetLeader() == leader.ID {
+				if id != leader.ID && st.GetState() != string(raft.Follower) {
+					t.Fatalf("%s is %s, want follower", id, st.GetState())
+				}
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("%s follows %q, want %s", id, st.GetLeader(), leader.ID)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	res, err := followerOf(c, leader).Harness.Client.GetOrderUnary(context.Background(), &pb.Request{Query: "apple"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	harnesstest.ExpectResults(t, res.GetResults(), harness.AppleResults...)
+}
+
+// TestForward checks that writes to a follower are forwarded to the leader
+// and applied by every member, with the errors of the catalog.
+func TestForward(t *testing.T) {
+	c, leader := startCluster(t, 0)
+
+	ctx := context.Background()
+	follower := followerOf(c, leader).Harness
+	added, err := follower.AddOrder("dragon fruit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := follower.Client.UpdateOrder(ctx, &pb.Order{Id: "3", Name: "blood orange"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := follower.Client.DeleteOrder(ctx, &pb.OrderID{Id: "2"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = follower.Client.AddOrder(ctx, &pb.Order{Id: added.GetId(), Name: "dragon fruit"})
+	harnesstest.ExpectCode(t, err, codes.AlreadyExists)
+	_, err = follower.Client.DeleteOrder(ctx, &pb.OrderID{Id: "2"})
+	harnesstest.ExpectCode(t, err, codes.NotFound)
+	waitConverged(t, c, raftIDs, "dragon fruit", "blood orange")
+	got, err := leader.Harness.Client.GetOrderByID(ctx, &pb.OrderID{Id: added.GetId()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.GetCreated().AsTime().Equal(added.GetCreated().AsTime()) {
+		t.Fatalf("leader holds %v, follower added %v", got, added)
+	}
+}
+
+// TestFailover checks that a leader cut off from the majority can no longer
+// commit writes, that the majority elects another leader taking writes, and
+// that the old leader converges once the partition heals.
+func TestFailover(t *testing.T) {
+	c, leader := startCluster(t, 0)
+
+	var majority []string
+	for _, id := range raftIDs {
+		if id != leader.ID {
+			majority = append(majority, id)
+		}
+	}
+	c.Partition([]string{leader.ID}, majority)
+	_, err := leader.Harness.AddOrder("star fruit")
+	harnesstest.ExpectCode(t, err, codes.Unavailable)
+
+	next, err := c.Leader(leaderTimeout, majority...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addOrder(t, next.Harness, "dragon fruit")
+	c.Heal()
+	waitConverged(t, c, raftIDs, "dragon fruit")
+	// The write the old leader could not commit was dropped with its log.
+	for _, order := range leader.Harness.Store.List() {
+		if order.Name == "star fruit" {
+			t.Fatal("uncommitted write of the old leader was applied")
+		}
+	}
+}
+
+// TestLinearizableRead checks that a member cut off from the leader still
+// serves stale reads but fails linearizable ones, and that linearizable
+// reads from a follower see the writes acknowledged before them.
+func TestLinearizableRead(t *testing.T) {
+	c, leader := startCluster(t, 0)
+
+	follower := followerOf(c, leader).Harness
+	addOrder(t, leader.Harness, "dragon fruit")
+	ctx := context.Background()
+	res, err := follower.Client.GetOrderUnary(ctx, &pb.Request{
+		Query:       "dragon",
+		Consistency: pb.ReadConsistency_READ_CONSISTENCY_LINEARIZABLE,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	harnesstest.ExpectResults(t, res.GetResults(), "dragon fruit")
+
+	lonely := followerOf(c, leader)
+	c.Partition([]string{lonely.ID})
+	res, err = lonely.Harness.Client.GetOrderUnary(ctx, &pb.Request{Query: "dragon"})
+	if err != nil {
+		t.Fatalf("stale read: %v", err)
+	}
+	harnesstest.ExpectResults(t, res.GetResults(), "dragon fruit")
+	readCtx, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
+	defer cancel()
+	_, err = lonely.Harness.Client.GetOrderByID(readCtx, &pb.OrderID{
+		Id:          "1",
+		Consistency: pb.ReadConsistency_READ_CONSISTENCY_LINEARIZABLE,
+	})
+	if code := status.Code(err); code != codes.Unavailable && code != codes.DeadlineExceeded {
+		t.Fatalf("linearizable read of an isolated member returned %v, want unavailable", err)
+	}
+}
+
+// TestDrops checks that writes go through a network losing a fifth of the
+// messages, retried by the client until committed, and that every member
+// ends up with all of them exactly once.
+func TestDrops(t *testing.T) {
+	c, _ := startCluster(t, 0)
+
+	c.Drop(0.2)
+	var names []string
+	for i := 0; i < 10; i++ {
+		name := fmt.Sprintf("fruit %d", i)
+		member := c.Member(raftIDs[i%len(raftIDs)]).Harness
+		// A write whose reply was lost may have been committed, so retries
+		// carry the id of the first attempt.
+		order := &pb.Order{Id: fmt.Sprintf("drop-%d", i), Name: name}
+		deadline := time.Now().Add(leaderTimeout)
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), harness.HandledTimeout)
+			_, err := member.Client.AddOrder(ctx, order)
+			cancel()
+			if err == nil || status.Code(err) == codes.AlreadyExists {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("write of %q: %v", name, err)
+			}
+		}
+		names = append(names, name)
+	}
+	c.Drop(0)
+	waitConverged(t, c, raftIDs, names...)
+	if got := len(c.Member(raftIDs[0]).Harness.Store.List()); got != len(harness.Fruits)+len(names) {
+		t.Fatalf("catalog holds %d orders, want %d", got, len(harness.Fruits)+len(names))
+	}
+}
+
+// TestSnapshot checks that members compact their log into snapshots, and
+// that a member down while the log was compacted catches up from one.
+func TestSnapshot(t *testing.T) {
+	const threshold = 5
+	c, leader := startCluster(t, threshold)
+
+	stopped := followerOf(c, leader)
+	c.Stop(stopped.ID)
+	var names []string
+	for i := 0; i < 3*threshold; i++ {
+		name := fmt.Sprintf("fruit %d", i)
+		addOrder(t, leader.Harness, name)
+		names = append(names, name)
+	}
+	if raftStatus(t, leader).GetSnapshotIndex() == 0 {
+		t.Fatalf("leader took no snapshot after %d writes", len(names))
+	}
+	if _, err := c.Restart(stopped); err != nil {
+		t.Fatal(err)
+	}
+	waitConverged(t, c, raftIDs, names...)
+}
+
+// TestMembership checks that a member added to the cluster receives the
+// catalog and takes writes, and that a removed member no longer counts
+// towards the majority.
+func TestMembership(t *testing.T) {
+	c, leader := startCluster(t, 0)
+
+	addOrder(t, leader.Harness, "dragon fruit")
+	joined, err := c.Join("raft-4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), harness.HandledTimeout)
+	defer cancel()
+	follower := followerOf(c, leader)
+	st, err := follower.Harness.Raft.AddMember(ctx, &pb.MembershipRequest{Member: &pb.Member{Id: joined.ID, Address: joined.ID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(st.GetMembers()) != 4 {
+		t.Fatalf("cluster has members %v after adding %s", st.GetMembers(), joined.ID)
+	}
+	_, err = follower.Harness.Raft.AddMember(ctx, &pb.MembershipRequest{Member: &pb.Member{Id: joined.ID, Address: joined.ID}})
+	harnesstest.ExpectCode(t, err, codes.AlreadyExists)
+	all := append(slices.Clone(raftIDs), joined.ID)
+	waitConverged(t, c, all, "dragon fruit")
+	addOrder(t, joined.Harness, "star fruit")
+
+	// Once a member is removed, the three left make a majority of two even
+	// with a third one down.
+	var others []string
+	for _, id := range all {
+		if id != leader.ID && id != follower.ID {
+			others = append(others, id)
+		}
+	}
+	if _, err := leader.Harness.Raft.RemoveMember(ctx, &pb.MembershipRequest{Member: &pb.Member{Id: others[0]}}); err != nil {
+		t.Fatalf("remove %s: %v", others[0], err)
+	}
+	c.Stop(others[0])
+	_, err = leader.Harness.Raft.RemoveMember(ctx, &pb.MembershipRequest{Member: &pb.Member{Id: others[0]}})
+	harnesstest.ExpectCode(t, err, codes.NotFound)
+	c.Stop(others[1])
+	addOrder(t, leader.Harness, "passion fruit")
+	waitConverged(t, c, []string{leader.ID, follower.ID}, "dragon fruit", "star fruit", "passion fruit")
+}
+
+// TestRestart checks that a whole cluster restarted from its storage comes
+// back with every committed write.
+func TestRestart(t *testing.T) {
+	c, leader := startCluster(t, 4)
+
+	var names []string
+	for i := 0; i < 6; i++ {
+		name := fmt.Sprintf("fruit %d", i)
+		addOrder(t, leader.Harness, name)
+		names = append(names, name)
+	}
+	var members []*harness.Member
+	for _, id := range raftIDs {
+		members = append(members, c.Member(id))
+		c.Stop(id)
+	}
+	for _, m := range members {
+		if _, err := c.Restart(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	leader, err := c.Leader(leaderTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addOrder(t, leader.Harness, "dragon fruit")
+	waitConverged(t, c, raftIDs, append(names, "dragon fruit")...)
+}
@@ -0,0 +1,416 @@
+package raft
+
+import (
+	"context"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "dist-grpc/pkg/proto"
+)
+
+// peer is the progress of the leader's log on another member.
+type peer struct {
+	id, addr string
+	// next is the index of the next entry to send, and match the last one
+	// the member is known to have.
+	next, match uint64
+	// ackRound is the last heartbeat round the member answered, and lastAck
+	// when it last answered at all.
+	ackRound uint64
+	lastAck  time.Time
+	stop     context.CancelFunc
+}
+
+// appendEntry appends an entry of the current term to the leader's log,
+// which persist saves meanwhile it is replicated. It is called with mu held.
+func (n *Node) appendEntry(entry *pb.RaftEntry) uint64 {
+	last, _ := n.log.last()
+	entry.Index, entry.Term = last+1, n.term
+	n.log.append(entry)
+	if entry.GetType() == pb.EntryType_ENTRY_TYPE_MEMBERS {
+		n.setMembers()
+	}
+	n.advanceCommit()
+	n.broadcast()
+	return entry.Index
+}
+
+// persist saves the entries the leader appends until it stops leading, out
+// of mu so that appending and replicating go on meanwhile. A leader that
+// cannot save its log steps down.
+func (n *Node) persist(ctx context.Context) {
+	for ctx.Err() == nil {
+		n.appendMu.Lock()
+		n.mu.Lock()
+		last, _ := n.log.last()
+		entries := n.log.between(n.saved+1, last, maxBatch)
+		changed := n.changed
+		n.mu.Unlock()
+		if len(entries) == 0 {
+			n.appendMu.Unlock()
+			select {
+			case <-ctx.Done():
+			case <-changed:
+			}
+			continue
+		}
+
+		err := n.saveEntries(entries)
+		n.mu.Lock()
+		if err != nil {
+			log.Error("Stepping down", "err", err)
+			if ctx.Err() == nil {
+				_ = n.stepDown(n.term)
+			}
+		} else {
+			n.saved = max(n.saved, entries[len(entries)-1].GetIndex())
+			n.advanceCommit()
+			n.broadcast()
+		}
+		n.mu.Unlock()
+		n.appendMu.Unlock()
+	}
+}
+
+// startPeers starts replicating to the members. It is called with mu held.
+func (n *Node) startPeers(ctx context.Context) {
+	last, _ := n.log.last()
+	for _, m := range n.members {
+		if m.GetId() == n.id {
+			continue
+		}
+		pctx, cancel := context.WithCancel(ctx)
+		p := &peer{id: m.GetId(), addr: m.GetAddress(), next: last + 1, lastAck: time.Now(), stop: cancel}
+		n.peers[p.id] = p
+		go n.replicate(pctx, p, n.term)
+	}
+}
+
+// updatePeers follows a change of membership on the leader, which starts
+// replicating to the new members and stops for those removed. It is called
+// with mu held.
+func (n *Node) updatePeers() {
+	for id, p := range n.peers {
+		if !n.isMember(id) {
+			p.stop()
+			delete(n.peers, id)
+		}
+	}
+	for _, m := range n.members {
+		if _, ok := n.peers[m.GetId()]; ok || m.GetId() == n.id {
+			continue
+		}
+		// A new member starts from a snapshot, as it does not hold the
+		// catalog the cluster started from.
+		ctx, cancel := context.WithCancel(context.Background())
+		p := &peer{id: m.GetId(), addr: m.GetAddress(), next: 0, lastAck: time.Now(), stop: cancel}
+		n.peers[p.id] = p
+		go n.replicate(ctx, p, n.term)
+	}
+}
+
+// stopLeading stops the replication and fails the writes still waiting
+// for it. It is called with mu held.
+func (n *Node) stopLeading() {
+	if n.stop != nil {
+		n.stop()
+		n.stop = nil
+	}
+	for _, p := range n.peers {
+		p.stop()
+	}
+	n.peers = nil
+	for index, p := range n.proposals {
+		p.done <- errLeadershipLost
+		delete(n.proposals, index)
+	}
+}
+
+// replicate sends the log to the member until the node stops leading it,
+// in batches, as a snapshot when the entries it misses were compacted, or
+// as empty heartbeats when it is up to date.
+func (n *Node) replicate(ctx context.Context, p *peer, term uint64) {
+	for ctx.Err() == nil {
+		n.mu.Lock()
+		round := n.round
+		base, _ := n.log.base()
+		var appendReq *pb.AppendEntriesRequest
+		var snapshot *pb.RaftSnapshot
+		if p.next <= base {
+			snapshot = n.log.snapshot
+		} else {
+			prevIndex := p.next - 1
+			prevTerm, _ := n.log.termAt(prevIndex)
+			last, _ := n.log.last()
+			appendReq = &pb.AppendEntriesRequest{
+				Term:        term,
+				Leader:      n.id,
+				PrevIndex:   prevIndex,
+				PrevTerm:    prevTerm,
+				Entries:     n.log.between(p.next, last, maxBatch),
+				CommitIndex: n.commitIndex,
+			}
+		}
+		changed := n.changed
+		n.mu.Unlock()
+
+		var respTerm, match uint64
+		success := false
+		client, err := n.client(p.addr)
+		if err == nil {
+			callCtx, cancel := context.WithTimeout(ctx, n.config.ElectionTimeout)
+			if snapshot != nil {
+				var resp *pb.InstallSnapshotResponse
+				resp, err = client.InstallSnapshot(callCtx, &pb.InstallSnapshotRequest{Term: term, Leader: n.id, Snapshot: snapshot})
+				respTerm, success, match = resp.GetTerm(), err == nil, snapshot.GetIndex()
+			} else {
+				var resp *pb.AppendEntriesResponse
+				resp, err = client.AppendEntries(callCtx, appendReq)
+				respTerm, success = resp.GetTerm(), resp.GetSuccess()
+				match = resp.GetLastIndex()
+			}
+			cancel()
+		}
+
+		n.mu.Lock()
+		if ctx.Err() != nil {
+			n.mu.Unlock()
+			return
+		}
+		if err == nil {
+			p.lastAck = time.Now()
+		}
+		switch {
+		case err != nil:
+			log.Debug("Failed to replicate", "member", p.id, "err", err)
+		case respTerm > term:
+			if err := n.stepDown(respTerm); err != nil {
+				log.Error("Failed to step down", "err", err)
+			}
+			n.mu.Unlock()
+			return
+		case success:
+			if snapshot != nil {
+				log.Info("Sent snapshot", "member", p.id, "index", match)
+			}
+			p.match = max(p.match, match)
+			p.next = p.match + 1
+			p.ackRound = max(p.ackRound, round)
+			n.advanceCommit()
+			n.broadcast()
+		default:
+			// The member answers with where its log ends or diverges.
+			p.ackRound = max(p.ackRound, round)
+			p.next = max(min(p.next-1, match+1), 1)
+			n.broadcast()
+		}
+		last, _ := n.log.last()
+		more := err == nil && p.next <= last
+		n.mu.Unlock()
+		if more {
+			continue
+		}
+		// An unreachable member is retried on the next heartbeat rather
+		// than on every change.
+		if err != nil {
+			changed = nil
+		}
+		select {
+		case <-ctx.Done():
+		case <-changed:
+		case <-time.After(n.config.Heartbeat):
+		}
+	}
+}
+
+// advanceCommit commits the last entry of the current term a majority has,
+// along with every entry before it. It is called with mu held.
+func (n *Node) advanceCommit() {
+	if n.state != Leader {
+		return
+	}
+	last, _ := n.log.last()
+	for index := last; index > n.commitIndex; index-- {
+		if term, _ := n.log.termAt(index); term != n.term {
+			return
+		}
+		count := 0
+		for _, m := range n.members {
+			if (m.GetId() == n.id && n.saved >= index) || (n.peers[m.GetId()] != nil && n.peers[m.GetId()].match >= index) {
+				count++
+			}
+		}
+		if count >= n.quorum() {
+			n.commitIndex = index
+			n.broadcast()
+			// A leader removed from the cluster leaves once its removal
+			// is committed.
+			if !n.isMember(n.id) && n.configIndex <= index {
+				log.Warn("Removed from the cluster")
+				_ = n.stepDown(n.term)
+			}
+			return
+		}
+	}
+}
+
+// AppendEntries saves the entries before appending them, out of mu, and
+// fails without taking them when they cannot be saved, so that the leader
+// does not count them as replicated.
+func (n *Node) AppendEntries(ctx context.Context, req *pb.AppendEntriesRequest) (*pb.AppendEntriesResponse, error) {
+	n.appendMu.Lock()
+	defer n.appendMu.Unlock()
+	n.mu.Lock()
+	last, lastTerm := n.log.last()
+	if req.GetTerm() < n.term {
+		defer n.mu.Unlock()
+		return &pb.AppendEntriesResponse{Term: n.term, LastIndex: last}, nil
+	}
+	if err := n.follow(req.GetTerm(), req.GetLeader()); err != nil {
+		defer n.mu.Unlock()
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	base, _ := n.log.base()
+	if req.GetPrevIndex() > last {
+		defer n.mu.Unlock()
+		return &pb.AppendEntriesResponse{Term: n.term, LastIndex: last}, nil
+	}
+	if req.GetPrevIndex() >= base {
+		if term, _ := n.log.termAt(req.GetPrevIndex()); term != req.GetPrevTerm() {
+			defer n.mu.Unlock()
+			return &pb.AppendEntriesResponse{Term: n.term, LastIndex: req.GetPrevIndex() - 1}, nil
+		}
+	}
+
+	// The entries the log has in the same term are skipped, unless they
+	// are not saved yet, as the node led that term.
+	var appended []*pb.RaftEntry
+	for i, entry := range req.GetEntries() {
+		if entry.GetIndex() <= base {
+			continue
+		}
+		if term, _ := n.log.termAt(entry.GetIndex()); entry.GetIndex() <= min(last, n.saved) && term == entry.GetTerm() {
+			continue
+		}
+		appended = req.GetEntries()[i:]
+		break
+	}
+	term := n.term
+	n.mu.Unlock()
+
+	var err error
+	if len(appended) > 0 {
+		err = n.saveEntries(appended)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	// The node may have stood for election meanwhile, and appended to its
+	// log as the leader of a later term.
+	if now, nowTerm := n.log.last(); n.term != term || now != last || nowTerm != lastTerm {
+		if len(appended) > 0 {
+			n.saved = min(n.saved, appended[0].GetIndex()-1)
+		}
+		return &pb.AppendEntriesResponse{Term: n.term, LastIndex: now}, nil
+	}
+	for i, entry := range appended {
+		if entry.GetIndex() <= last {
+			if term, _ := n.log.termAt(entry.GetIndex()); term == entry.GetTerm() {
+				continue
+			}
+			// A conflicting entry and those after it were never
+			// committed, and give way to the leader's.
+			n.log.truncate(entry.GetIndex())
+		}
+		n.log.append(appended[i:]...)
+		break
+	}
+	if len(appended) > 0 {
+		n.saved = appended[len(appended)-1].GetIndex()
+		n.setMembers()
+	}
+	lastNew := req.GetPrevIndex() + uint64(len(req.GetEntries()))
+	if commit := min(req.GetCommitIndex(), lastNew); commit > n.commitIndex {
+		n.commitIndex = commit
+		n.broadcast()
+	}
+	return &pb.AppendEntriesResponse{Term: n.term, Success: true, LastIndex: lastNew}, nil
+}
+
+// follow makes the node a follower of the leader of the term, which is at
+// least the node's own. It is called with mu held.
+func (n *Node) follow(term uint64, leader string) error {
+	if err := n.stepDown(term); err != nil {
+		return err
+	}
+	if n.leader != leader {
+		n.leader = leader
+		log.Info("Following", "leader", leader, "term", term)
+		n.broadcast()
+	}
+	n.lastContact = time.Now()
+	n.resetElection()
+	return nil
+}
+
+// InstallSnapshot saves the snapshot before installing it, and fails
+// without installing it when it cannot be saved.
+func (n *Node) InstallSnapshot(ctx context.Context, req *pb.InstallSnapshotRequest) (*pb.InstallSnapshotResponse, error) {
+	n.appendMu.Lock()
+	defer n.appendMu.Unlock()
+	n.applyMu.Lock()
+	defer n.applyMu.Unlock()
+	n.mu.Lock()
+	if req.GetTerm() < n.term {
+		defer n.mu.Unlock()
+		return &pb.InstallSnapshotResponse{Term: n.term}, nil
+	}
+	if err := n.follow(req.GetTerm(), req.GetLeader()); err != nil {
+		defer n.mu.Unlock()
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	snapshot := req.GetSnapshot()
+	if snapshot.GetIndex() < n.lastApplied {
+		defer n.mu.Unlock()
+		return &pb.InstallSnapshotResponse{Term: n.term}, nil
+	}
+	// The saved entries that follow the snapshot are kept with it when the
+	// log agrees with it.
+	var rest []*pb.RaftEntry
+	if term, ok := n.log.termAt(snapshot.GetIndex()); ok && term == snapshot.GetTerm() {
+		rest = slices.Clone(n.log.between(snapshot.GetIndex()+1, n.saved, math.MaxInt))
+	}
+	n.mu.Unlock()
+
+	// Only the holders of appendMu change the saved log and only the
+	// applier, held off by applyMu, the catalog meanwhile.
+	if err := n.saveSnapshot(snapshot, rest); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if err := replaceCatalog(n.store, snapshot); err != nil {
+		return nil, err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.log.compact(snapshot)
+	n.saved = snapshot.GetIndex()
+	if len(rest) > 0 {
+		n.saved = rest[len(rest)-1].GetIndex()
+	}
+	n.commitIndex = max(n.commitIndex, snapshot.GetIndex())
+	n.lastApplied = snapshot.GetIndex()
+	n.setMembers()
+	n.broadcast()
+	log.Info("Installed snapshot", "index", snapshot.GetIndex(), "orders", len(snapshot.GetOrders()), "leader", req.GetLeader())
+	return &pb.InstallSnapshotResponse{Term: n.term}, nil
+}
@@ -0,0 +1,272 @@
+package raft
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+
+	pb "dist-grpc/pkg/proto"
+)
+
+// Storage keeps the state a node must not forget across restarts: its term,
+// its vote, its snapshot and its log. Each change saves only what changed,
+// so that appending an entry does not write the whole catalog again. Load
+// returns nil when no snapshot was saved yet.
+type Storage interface {
+	Load() (*pb.RaftState, error)
+	SaveTerm(term uint64, votedFor string) error
+	// Append saves entries following the saved ones, replacing those from
+	// the index of the first entry on.
+	Append(entries []*pb.RaftEntry) error
+	// SaveSnapshot saves the snapshot along with the entries that follow it,
+	// which replace the saved log.
+	SaveSnapshot(snapshot *pb.RaftSnapshot, entries []*pb.RaftEntry) error
+}
+
+// MemoryStorage keeps the state in memory, surviving the restart of a node
+// within the process, as in the harness.
+type MemoryStorage struct {
+	mu    sync.Mutex
+	state *pb.RaftState
+}
+
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{state: &pb.RaftState{}}
+}
+
+func (s *MemoryStorage) Load() (*pb.RaftState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state.GetSnapshot() == nil {
+		return nil, nil
+	}
+	return &pb.RaftState{
+		Term:     s.state.GetTerm(),
+		VotedFor: s.state.GetVotedFor(),
+		Snapshot: s.state.GetSnapshot(),
+		Entries:  slices.Clone(s.state.GetEntries()),
+	}, nil
+}
+
+func (s *MemoryStorage) SaveTerm(term uint64, votedFor string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.Term, s.state.VotedFor = term, votedFor
+	return nil
+}
+
+func (s *MemoryStorage) Append(entries []*pb.RaftEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.Entries = appendEntries(s.state.Entries, entries)
+	return nil
+}
+
+func (s *MemoryStorage) SaveSnapshot(snapshot *pb.RaftSnapshot, entries []*pb.RaftEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.Snapshot, s.state.Entries = snapshot, slices.Clone(entries)
+	return nil
+}
+
+// appendEntries appends the entries to the log, dropping those of the log
+// from the index of the first entry on.
+func appendEntries(log, entries []*pb.RaftEntry) []*pb.RaftEntry {
+	if len(entries) == 0 {
+		return log
+	}
+	first := entries[0].GetIndex()
+	end := len(log)
+	for end > 0 && log[end-1].GetIndex() >= first {
+		end--
+	}
+	return append(log[:end], entries...)
+}
+
+// FileStorage keeps the term and vote, the snapshot and the log in separate
+// files of a directory. Entries are appended to the log file, and the log is
+// only rewritten with a new snapshot.
+type FileStorage struct {
+	dir string
+}
+
+const (
+	termFile     = "raft.term"
+	snapshotFile = "raft.snapshot"
+	logFile      = "raft.log"
+)
+
+func NewFileStorage(dir string) (*FileStorage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create raft directory: %w", err)
+	}
+	return &FileStorage{dir: dir}, nil
+}
+
+func (s *FileStorage) path(name string) string {
+	return filepath.Join(s.dir, name)
+}
+
+func (s *FileStorage) Load() (*pb.RaftState, error) {
+	snapshot := &pb.RaftSnapshot{}
+	if ok, err := s.read(snapshotFile, snapshot); err != nil || !ok {
+		return nil, err
+	}
+	state := &pb.RaftState{}
+	if _, err := s.read(termFile, state); err != nil {
+		return nil, err
+	}
+	state.Snapshot = snapshot
+	entries, err := s.readLog()
+	if err != nil {
+		return nil, err
+	}
+	// A log left over from before the snapshot only continues it when it
+	// agrees with the snapshot on its last entry.
+	for i, entry := range entries {
+		if entry.GetIndex() == snapshot.GetIndex() && entry.GetTerm() != snapshot.GetTerm() {
+			break
+		}
+		if entry.GetIndex() > snapshot.GetIndex() {
+			state.Entries = entries[i:]
+			break
+		}
+	}
+	return state, nil
+}
+
+// read parses the file into msg, reporting false when it does not exist.
+func (s *FileStorage) read(name string, msg proto.Message) (bool, error) {
+	data, err := os.ReadFile(s.path(name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read raft state: %w", err)
+	}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return false, fmt.Errorf("failed to parse raft state %s: %w", s.path(name), err)
+	}
+	return true, nil
+}
+
+// readLog replays the records of the log file. An entry replaces those from
+// its index on, as appended after a conflict, and a record cut short by a
+// crash is dropped from the file.
+func (s *FileStorage) readLog() ([]*pb.RaftEntry, error) {
+	data, err := os.ReadFile(s.path(logFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read raft log: %w", err)
+	}
+	var entries []*pb.RaftEntry
+	offset := 0
+	for offset < len(data) {
+		record, n := protowire.ConsumeBytes(data[offset:])
+		if n < 0 {
+			break
+		}
+		entry := &pb.RaftEntry{}
+		if err := proto.Unmarshal(record, entry); err != nil {
+			break
+		}
+		entries = appendEntries(entries, []*pb.RaftEntry{entry})
+		offset += n
+	}
+	if offset < len(data) {
+		if err := os.Truncate(s.path(logFile), int64(offset)); err != nil {
+			return nil, fmt.Errorf("failed to repair raft log: %w", err)
+		}
+	}
+	return entries, nil
+}
+
+func (s *FileStorage) SaveTerm(term uint64, votedFor string) error {
+	return s.write(termFile, &pb.RaftState{Term: term, VotedFor: votedFor})
+}
+
+func (s *FileStorage) Append(entries []*pb.RaftEntry) error {
+	data, err := encodeEntries(entries)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.path(logFile), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to append to raft log: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to append to raft log: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to append to raft log: %w", err)
+	}
+	return f.Close()
+}
+
+// SaveSnapshot writes the snapshot before the log, so that a crash in
+// between leaves a log that Load trims to the snapshot.
+func (s *FileStorage) SaveSnapshot(snapshot *pb.RaftSnapshot, entries []*pb.RaftEntry) error {
+	if err := s.write(snapshotFile, snapshot); err != nil {
+		return err
+	}
+	data, err := encodeEntries(entries)
+	if err != nil {
+		return err
+	}
+	return s.writeFile(logFile, data)
+}
+
+func encodeEntries(entries []*pb.RaftEntry) ([]byte, error) {
+	var data []byte
+	for _, entry := range entries {
+		record, err := proto.Marshal(entry)
+		if err != nil {
+			return nil, err
+		}
+		data = protowire.AppendBytes(data, record)
+	}
+	return data, nil
+}
+
+func (s *FileStorage) write(name string, msg proto.Message) error {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return s.writeFile(name, data)
+}
+
+// writeFile replaces the file with data through a synced temporary file, so
+// that a crash leaves either the former or the new content.
+func (s *FileStorage) writeFile(name string, data []byte) error {
+	tmp, err := os.CreateTemp(s.dir, name+".*")
+	if err != nil {
+		return fmt.Errorf("failed to save raft state: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save raft state: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save raft state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save raft state: %w", err)
+	}
+	return os.Rename(tmp.Name(), s.path(name))
+}
@@ -0,0 +1,288 @@
+package raft
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"dist-grpc/pkg/catalog"
+	pb "dist-grpc/pkg/proto"
+)
+
+func entry(index, term uint64) *pb.RaftEntry {
+	return &pb.RaftEntry{Index: index, Term: term, Type: pb.EntryType_ENTRY_TYPE_COMMAND, Order: &pb.Order{Id: "1", Name: "apple"}}
+}
+
+func expectLog(t *testing.T, state *pb.RaftState, want ...[2]uint64) {
+	t.Helper()
+	if len(state.GetEntries()) != len(want) {
+		t.Fatalf("loaded %d entries, want %d", len(state.GetEntries()), len(want))
+	}
+	for i, e := range state.GetEntries() {
+		if e.GetIndex() != want[i][0] || e.GetTerm() != want[i][1] {
+			t.Fatalf("entry %d is %d@%d, want %d@%d", i, e.GetIndex(), e.GetTerm(), want[i][0], want[i][1])
+		}
+	}
+}
+
+func TestFileStorage(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewFileStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state, err := s.Load(); err != nil || state != nil {
+		t.Fatalf("empty storage loaded %v, %v", state, err)
+	}
+	if err := s.SaveSnapshot(&pb.RaftSnapshot{Orders: []*pb.Order{{Id: "1", Name: "apple"}}}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveTerm(2, "a"); err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range []*pb.RaftEntry{entry(1, 1), entry(2, 1), entry(3, 1)} {
+		if err := s.Append([]*pb.RaftEntry{e}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Entries of a new leader replace the conflicting ones.
+	if err := s.Append([]*pb.RaftEntry{entry(2, 2), entry(3, 2)}); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.GetTerm() != 2 || state.GetVotedFor() != "a" || len(state.GetSnapshot().GetOrders()) != 1 {
+		t.Fatalf("loaded %v, want term 2, vote a and the snapshot", state)
+	}
+	expectLog(t, state, [2]uint64{1, 1}, [2]uint64{2, 2}, [2]uint64{3, 2})
+
+	// A record cut short by a crash is dropped, and appends go on after it.
+	f, err := os.OpenFile(filepath.Join(dir, logFile), os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte{0x20, 0x08}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if state, err = s.Load(); err != nil {
+		t.Fatal(err)
+	}
+	expectLog(t, state, [2]uint64{1, 1}, [2]uint64{2, 2}, [2]uint64{3, 2})
+	if err := s.Append([]*pb.RaftEntry{entry(4, 2)}); err != nil {
+		t.Fatal(err)
+	}
+	if state, err = s.Load(); err != nil {
+		t.Fatal(err)
+	}
+	expectLog(t, state, [2]uint64{1, 1}, [2]uint64{2, 2}, [2]uint64{3, 2}, [2]uint64{4, 2})
+
+	// A snapshot rewrites the log with the entries that follow it.
+	if err := s.SaveSnapshot(&pb.RaftSnapshot{Index: 3, Term: 2}, []*pb.RaftEntry{entry(4, 2)}); err != nil {
+		t.Fatal(err)
+	}
+	if state, err = s.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if state.GetSnapshot().GetIndex() != 3 || state.GetTerm() != 2 {
+		t.Fatalf("loaded %v, want the snapshot at 3 in term 2", state)
+	}
+	expectLog(t, state, [2]uint64{4, 2})
+}
+
+// TestFileStorageStaleLog checks that a log the snapshot was saved over
+// without rewriting it, as after a crash, only keeps what follows the
+// snapshot when it agrees with it.
+func TestFileStorageStaleLog(t *testing.T) {
+	s, err := NewFileStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Append([]*pb.RaftEntry{entry(1, 1), entry(2, 1), entry(3, 1)}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.write(snapshotFile, &pb.RaftSnapshot{Index: 2, Term: 1}); err != nil {
+		t.Fatal(err)
+	}
+	state, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectLog(t, state, [2]uint64{3, 1})
+
+	if err := s.write(snapshotFile, &pb.RaftSnapshot{Index: 2, Term: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if state, err = s.Load(); err != nil {
+		t.Fatal(err)
+	}
+	expectLog(t, state)
+}
+
+var errDiskFull = errors.New("disk full")
+
+// faultyStorage fails the saves of each kind while it is told to, and holds
+// the appends until hold is closed when it is set.
+type faultyStorage struct {
+	*MemoryStorage
+	failTerm, failAppend, failSnapshot atomic.Bool
+	hold                               chan struct{}
+}
+
+func (s *faultyStorage) SaveTerm(term uint64, votedFor string) error {
+	if s.failTerm.Load() {
+		return errDiskFull
+	}
+	return s.MemoryStorage.SaveTerm(term, votedFor)
+}
+
+func (s *faultyStorage) Append(entries []*pb.RaftEntry) error {
+	if s.hold != nil {
+		<-s.hold
+	}
+	if s.failAppend.Load() {
+		return errDiskFull
+	}
+	return s.MemoryStorage.Append(entries)
+}
+
+func (s *faultyStorage) SaveSnapshot(snapshot *pb.RaftSnapshot, entries []*pb.RaftEntry) error {
+	if s.failSnapshot.Load() {
+		return errDiskFull
+	}
+	return s.MemoryStorage.SaveSnapshot(snapshot, entries)
+}
+
+// TestFailedSaves checks that a follower which cannot save refuses the vote
+// and fails the RPC, without taking the term, the vote, the entries or the
+// snapshot it could not save.
+func TestFailedSaves(t *testing.T) {
+	storage := &faultyStorage{MemoryStorage: NewMemoryStorage()}
+	members := []*pb.Member{{Id: "a", Address: "a"}, {Id: "b", Address: "b"}, {Id: "c", Address: "c"}}
+	n, err := New(catalog.NewMemoryStore(nil), members, Config{ID: "b", ElectionTimeout: time.Hour, Storage: storage})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer n.Close()
+	ctx := context.Background()
+	expect := func(what string, err error, term uint64, votedFor string, last uint64) {
+		t.Helper()
+		if status.Code(err) != codes.Internal {
+			t.Errorf("%s: got %v, want Internal", what, err)
+		}
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		if got, _ := n.log.last(); n.term != term || n.votedFor != votedFor || got != last || n.saved != last {
+			t.Errorf("%s: got term %d, vote %q, log at %d saved at %d, want term %d, vote %q, log at %d",
+				what, n.term, n.votedFor, got, n.saved, term, votedFor, last)
+		}
+	}
+
+	storage.failTerm.Store(true)
+	_, err = n.RequestVote(ctx, &pb.VoteRequest{Term: 2, Candidate: "a"})
+	expect("vote in a new term", err, 0, "", 0)
+	_, err = n.AppendEntries(ctx, &pb.AppendEntriesRequest{Term: 1, Leader: "a", Entries: []*pb.RaftEntry{entry(1, 1)}})
+	expect("append in a new term", err, 0, "", 0)
+	storage.failTerm.Store(false)
+	if _, err := n.AppendEntries(ctx, &pb.AppendEntriesRequest{Term: 1, Leader: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	// The leader of the term is heard from, so a vote is only asked for
+	// in a later one.
+	n.mu.Lock()
+	n.leader = ""
+	n.mu.Unlock()
+	storage.failTerm.Store(true)
+	_, err = n.RequestVote(ctx, &pb.VoteRequest{Term: 1, Candidate: "c"})
+	expect("vote", err, 1, "", 0)
+	storage.failTerm.Store(false)
+
+	storage.failAppend.Store(true)
+	_, err = n.AppendEntries(ctx, &pb.AppendEntriesRequest{Term: 1, Leader: "a", Entries: []*pb.RaftEntry{entry(1, 1)}})
+	expect("append", err, 1, "", 0)
+	storage.failAppend.Store(false)
+	resp, err := n.AppendEntries(ctx, &pb.AppendEntriesRequest{Term: 1, Leader: "a", Entries: []*pb.RaftEntry{entry(1, 1)}})
+	if err != nil || !resp.GetSuccess() || resp.GetLastIndex() != 1 {
+		t.Fatalf("append got %v, %v, want success at 1", resp, err)
+	}
+
+	storage.failSnapshot.Store(true)
+	snapshot := &pb.RaftSnapshot{Index: 5, Term: 1, Members: members, Orders: []*pb.Order{{Id: "1", Name: "apple"}}}
+	_, err = n.InstallSnapshot(ctx, &pb.InstallSnapshotRequest{Term: 1, Leader: "a", Snapshot: snapshot})
+	expect("snapshot", err, 1, "", 1)
+	if orders := n.List(); len(orders) != 0 {
+		t.Errorf("catalog holds %v after a snapshot that could not be saved", orders)
+	}
+	storage.failSnapshot.Store(false)
+	if _, err := n.InstallSnapshot(ctx, &pb.InstallSnapshotRequest{Term: 1, Leader: "a", Snapshot: snapshot}); err != nil {
+		t.Fatal(err)
+	}
+	if state, err := storage.Load(); err != nil || state.GetSnapshot().GetIndex() != 5 {
+		t.Fatalf("saved %v, %v, want the snapshot at 5", state, err)
+	}
+}
+
+// TestLeaderSavesOutsideLock checks that the leader saves its entries
+// without holding up the node, and only counts itself as having them once
+// they are saved.
+func TestLeaderSavesOutsideLock(t *testing.T) {
+	storage := &faultyStorage{MemoryStorage: NewMemoryStorage(), hold: make(chan struct{})}
+	n, err := New(catalog.NewMemoryStore(nil), []*pb.Member{{Id: "a", Address: "a"}}, Config{
+		ID:              "a",
+		ElectionTimeout: 20 * time.Millisecond,
+		Heartbeat:       10 * time.Millisecond,
+		Storage:         storage,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer n.Close()
+
+	current := func() *pb.RaftStatus {
+		t.Helper()
+		got := make(chan *pb.RaftStatus, 1)
+		go func() {
+			st, _ := n.Status(context.Background(), &pb.RaftStatusRequest{})
+			got <- st
+		}()
+		select {
+		case st := <-got:
+			return st
+		case <-time.After(time.Second):
+			t.Fatal("status held up while the leader saves its log")
+			return nil
+		}
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for current().GetState() != string(Leader) {
+		if time.Now().After(deadline) {
+			t.Fatal("no leader elected")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if st := current(); st.GetLastIndex() != 1 || st.GetCommitIndex() != 0 {
+		t.Fatalf("got last %d, commit %d while saving, want 1 and 0", st.GetLastIndex(), st.GetCommitIndex())
+	}
+
+	close(storage.hold)
+	for current().GetCommitIndex() != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("entry not committed once saved")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
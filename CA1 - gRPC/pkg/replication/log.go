@@ -0,0 +1,63 @@
+package replication
+
+import pb "dist-grpc/pkg/proto"
+
+// logLimit is how many entries a replica keeps. Older entries are dropped,
+// and followers lagging behind them catch up from a snapshot instead.
+const logLimit = 1024
+
+// entryLog holds the entries that follow base, the index of the last write
+// a snapshot or the starting catalog already covers.
+type entryLog struct {
+	base      uint64
+	baseEpoch uint64
+	entries   []*pb.LogEntry
+}
+
+func (l *entryLog) last() (index, epoch uint64) {
+	if len(l.entries) == 0 {
+		return l.base, l.baseEpoch
+	}
+	e := l.entries[len(l.entries)-1]
+	return e.GetIndex(), e.GetEpoch()
+}
+
+// epochAt returns the epoch of the entry at index, if the log still holds it.
+func (l *entryLog) epochAt(index uint64) (uint64, bool) {
+	last, _ := l.last()
+	switch {
+	case index == l.base:
+		return l.baseEpoch, true
+	case index < l.base || index > last:
+		return 0, false
+	}
+	return l.entries[index-l.base-1].GetEpoch(), true
+}
+
+// from returns the entries from index on, or false when some of them were
+// already dropped.
+func (l *entryLog) from(index uint64) ([]*pb.LogEntry, bool) {
+	if index <= l.base {
+		return nil, false
+	}
+	last, _ := l.last()
+	if index > last {
+		return nil, true
+	}
+	return l.entries[index-l.base-1:], true
+}
+
+func (l *entryLog) append(entry *pb.LogEntry) {
+	l.entries = append(l.entries, entry)
+	if len(l.entries) > logLimit {
+		drop := len(l.entries) - logLimit/2
+		l.base, l.baseEpoch = l.entries[drop-1].GetIndex(), l.entries[drop-1].GetEpoch()
+		l.entries = append([]*pb.LogEntry(nil), l.entries[drop:]...)
+	}
+}
+
+// reset empties the log, which now starts after index.
+func (l *entryLog) reset(index, epoch uint64) {
+	l.base, l.baseEpoch = index, epoch
+	l.entries = nil
+}
@@ -0,0 +1,358 @@
+// Package replication replicates an order catalog from a leader to its
+// followers. The leader applies every write to its own catalog, appends it
+// to a log and ships the log to the followers over the Replication service;
+// followers apply the entries they receive and serve reads, but leave writes
+// to the leader. A follower only takes over once it is promoted, which
+// starts a new epoch that fences off the former leader.
+package replication
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"dist-grpc/pkg/catalog"
+	pb "dist-grpc/pkg/proto"
+)
+
+type Role string
+
+const (
+	Leader   Role = "leader"
+	Follower Role = "follower"
+)
+
+const (
+	DefaultHeartbeat  = 500 * time.Millisecond
+	DefaultAckTimeout = 2 * time.Second
+)
+
+type Config struct {
+	// Self is the address other replicas and clients reach this replica at,
+	// which followers give as the leader in the errors of rejected writes.
+	Self string
+	// MinAcks is how many followers must have a write before the leader
+	// acknowledges it. With 0, writes are acknowledged once applied by the
+	// leader and shipped in the background.
+	MinAcks    int
+	AckTimeout time.Duration
+	// Heartbeat is how often an idle leader appends nothing to its followers,
+	// which tells them it is alive and retries those that failed.
+	Heartbeat time.Duration
+	Dial      func(addr string) (pb.ReplicationClient, error)
+}
+
+// Node is a replica of the catalog. It is the catalog.Store a server should
+// serve, and the Replication service other replicas call.
+type Node struct {
+	pb.UnimplementedReplicationServer
+	store  catalog.Store
+	config Config
+
+	// writeMu serializes the writes to the catalog with their log entries,
+	// so that the log holds them in the order they were applied.
+	writeMu sync.Mutex
+
+	mu        sync.Mutex
+	role      Role
+	epoch     uint64
+	leader    string
+	log       entryLog
+	followers []*follower
+	// changed is closed and replaced whenever the log grows or a follower
+	// acknowledges entries.
+	changed chan struct{}
+	stop    context.CancelFunc
+	clients map[string]pb.ReplicationClient
+}
+
+// NewNode returns a follower replicating into the store. It rejects writes
+// until it is made leader with Lead.
+func NewNode(store catalog.Store, config Config) *Node {
+	if config.Heartbeat <= 0 {
+		config.Heartbeat = DefaultHeartbeat
+	}
+	if config.AckTimeout <= 0 {
+		config.AckTimeout = DefaultAckTimeout
+	}
+	return &Node{
+		store:   store,
+		config:  config,
+		role:    Follower,
+		changed: make(chan struct{}),
+		clients: make(map[string]pb.ReplicationClient),
+	}
+}
+
+// Lead makes the node the leader of a new epoch, shipping its log to the
+// followers.
+func (n *Node) Lead(followers []string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.stopLeading()
+	n.epoch++
+	n.role, n.leader = Leader, n.config.Self
+	// The starting catalog is known to no follower, which catch up from a
+	// snapshot of it.
+	if last, _ := n.log.last(); last == 0 {
+		n.log.reset(0, n.epoch)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	n.stop = cancel
+	last, _ := n.log.last()
+	for _, addr := range followers {
+		if addr == n.config.Self {
+			continue
+		}
+		f := &follower{addr: addr, next: last + 1}
+		n.followers = append(n.followers, f)
+		go n.replicate(ctx, f, n.epoch)
+	}
+	n.broadcast()
+	log.Info("Leading the catalog", "epoch", n.epoch, "followers", followers)
+}
+
+// Close stops shipping the log to the followers.
+func (n *Node) Close() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.stopLeading()
+}
+
+func (n *Node) stopLeading() {
+	if n.stop != nil {
+		n.stop()
+		n.stop = nil
+	}
+	n.followers = nil
+}
+
+// follow makes the node follow the leader of the epoch, which is at least
+// the node's own.
+func (n *Node) follow(epoch uint64, leader string) {
+	if n.role == Leader {
+		log.Warn("Stepping down", "epoch", epoch, "leader", leader)
+		n.stopLeading()
+		n.broadcast()
+	}
+	n.role, n.epoch, n.leader = Follower, epoch, leader
+}
+
+func (n *Node) broadcast() {
+	close(n.changed)
+	n.changed = make(chan struct{})
+}
+
+func (n *Node) client(addr string) (pb.ReplicationClient, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if c, ok := n.clients[addr]; ok {
+		return c, nil
+	}
+	c, err := n.config.Dial(addr)
+	if err != nil {
+		return nil, err
+	}
+	n.clients[addr] = c
+	return c, nil
+}
+
+func (n *Node) List() []catalog.Order {
+	return n.store.List()
+}
+
+func (n *Node) Get(id string) (catalog.Order, error) {
+	return n.store.Get(id)
+}
+
+func (n *Node) Subscribe(fn func(catalog.Change)) func() {
+	return n.store.Subscribe(fn)
+}
+
+func (n *Node) Add(order catalog.Order) error {
+	return n.write(pb.Operation_OPERATION_ADD, order, func() error { return n.store.Add(order) })
+}
+
+func (n *Node) Update(order catalog.Order) error {
+	return n.write(pb.Operation_OPERATION_UPDATE, order, func() error { return n.store.Update(order) })
+}
+
+func (n *Node) Delete(id string) error {
+	return n.write(pb.Operation_OPERATION_DELETE, catalog.Order{ID: id}, func() error { return n.store.Delete(id) })
+}
+
+// write applies a write on the leader and appends it to the log, then waits
+// for MinAcks followers to have it.
+func (n *Node) write(op pb.Operation, order catalog.Order, apply func() error) error {
+	n.writeMu.Lock()
+	n.mu.Lock()
+	if n.role != Leader {
+		leader := n.leader
+		n.mu.Unlock()
+		n.writeMu.Unlock()
+		if leader == "" {
+			return fmt.Errorf("%w: no leader known", catalog.ErrNotLeader)
+		}
+		return fmt.Errorf("%w: writes go to %s", catalog.ErrNotLeader, leader)
+	}
+	n.mu.Unlock()
+	if err := apply(); err != nil {
+		n.writeMu.Unlock()
+		return err
+	}
+	n.mu.Lock()
+	index, _ := n.log.last()
+	index++
+	n.log.append(&pb.LogEntry{Index: index, Epoch: n.epoch, Op: op, Order: catalog.ToProto(order)})
+	n.broadcast()
+	n.mu.Unlock()
+	n.writeMu.Unlock()
+	return n.waitAcks(index)
+}
+
+func (n *Node) waitAcks(index uint64) error {
+	if n.config.MinAcks <= 0 {
+		return nil
+	}
+	timeout := time.NewTimer(n.config.AckTimeout)
+	defer timeout.Stop()
+	for {
+		n.mu.Lock()
+		acks := 0
+		for _, f := range n.followers {
+			if f.acked >= index {
+				acks++
+			}
+		}
+		changed := n.changed
+		n.mu.Unlock()
+		if acks >= n.config.MinAcks {
+			return nil
+		}
+		select {
+		case <-changed:
+		case <-timeout.C:
+			return fmt.Errorf("%w: acknowledged by %d of the %d followers required within %s",
+				catalog.ErrNotReplicated, acks, n.config.MinAcks, n.config.AckTimeout)
+		}
+	}
+}
+
+// apply replays a log entry on the catalog of a follower.
+func apply(store catalog.Store, entry *pb.LogEntry) error {
+	order := catalog.FromProto(entry.GetOrder())
+	switch entry.GetOp() {
+	case pb.Operation_OPERATION_ADD:
+		return store.Add(order)
+	case pb.Operation_OPERATION_UPDATE:
+		return store.Update(order)
+	case pb.Operation_OPERATION_DELETE:
+		return store.Delete(order.ID)
+	}
+	return fmt.Errorf("unknown operation %s", entry.GetOp())
+}
+
+// accept checks the epoch of a leader calling the node, and follows it when
+// it is current. It is called with mu held.
+func (n *Node) accept(epoch uint64, leader string) (bool, error) {
+	if epoch < n.epoch {
+		return false, nil
+	}
+	if epoch == n.epoch && n.role == Leader {
+		return false, status.Errorf(codes.FailedPrecondition, "%s also leads epoch %d", n.config.Self, epoch)
+	}
+	n.follow(epoch, leader)
+	return true, nil
+}
+
+func (n *Node) Append(ctx context.Context, req *pb.AppendRequest) (*pb.AppendResponse, error) {
+	n.writeMu.Lock()
+	defer n.writeMu.Unlock()
+	n.mu.Lock()
+	ok, err := n.accept(req.GetEpoch(), req.GetLeader())
+	lastIndex, lastEpoch := n.log.last()
+	resp := &pb.AppendResponse{Epoch: n.epoch, LastIndex: lastIndex, LastEpoch: lastEpoch}
+	n.mu.Unlock()
+	if err != nil || !ok {
+		return resp, err
+	}
+	// Entries are only appended right after the last one, as those already
+	// applied cannot be taken back: a diverging follower gets a snapshot.
+	if lastIndex != req.GetPrevIndex() || lastEpoch != req.GetPrevEpoch() {
+		return resp, nil
+	}
+	for _, entry := range req.GetEntries() {
+		// An entry the catalog does not take means it diverged from the
+		// leader, which is asked for a snapshot by leaving the entry out.
+		if err := apply(n.store, entry); err != nil {
+			log.Warn("Failed to apply replicated write", "index", entry.GetIndex(), "op", entry.GetOp(), "id", entry.GetOrder().GetId(), "err", err)
+			return nil, status.Errorf(codes.Aborted, "failed to apply entry %d: %v", entry.GetIndex(), err)
+		}
+		n.mu.Lock()
+		n.log.append(entry)
+		n.mu.Unlock()
+	}
+	resp.Success = true
+	if len(req.GetEntries()) > 0 {
+		last := req.GetEntries()[len(req.GetEntries())-1]
+		resp.LastIndex, resp.LastEpoch = last.GetIndex(), last.GetEpoch()
+		log.Debug("Applied replicated writes", "entries", len(req.GetEntries()), "index", resp.LastIndex)
+	}
+	return resp, nil
+}
+
+func (n *Node) InstallSnapshot(ctx context.Context, req *pb.Snapshot) (*pb.AppendResponse, error) {
+	n.writeMu.Lock()
+	defer n.writeMu.Unlock()
+	n.mu.Lock()
+	ok, err := n.accept(req.GetEpoch(), req.GetLeader())
+	lastIndex, lastEpoch := n.log.last()
+	resp := &pb.AppendResponse{Epoch: n.epoch, LastIndex: lastIndex, LastEpoch: lastEpoch}
+	n.mu.Unlock()
+	if err != nil || !ok {
+		return resp, err
+	}
+	orders := make([]catalog.Order, 0, len(req.GetOrders()))
+	for _, order := range req.GetOrders() {
+		orders = append(orders, catalog.FromProto(order))
+	}
+	if err := catalog.Replace(n.store, orders); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to install snapshot: %v", err)
+	}
+	n.mu.Lock()
+	n.log.reset(req.GetIndex(), req.GetIndexEpoch())
+	n.mu.Unlock()
+	log.Info("Installed snapshot", "index", req.GetIndex(), "orders", len(req.GetOrders()), "leader", req.GetLeader())
+	resp.Success, resp.LastIndex, resp.LastEpoch = true, req.GetIndex(), req.GetIndexEpoch()
+	return resp, nil
+}
+
+func (n *Node) Promote(ctx context.Context, req *pb.PromoteRequest) (*pb.ReplicationStatus, error) {
+	n.Lead(req.GetFollowers())
+	return n.Status(ctx, &pb.StatusRequest{})
+}
+
+func (n *Node) Status(ctx context.Context, req *pb.StatusRequest) (*pb.ReplicationStatus, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	last, _ := n.log.last()
+	res := &pb.ReplicationStatus{
+		Role:      string(n.role),
+		Epoch:     n.epoch,
+		Leader:    n.leader,
+		LastIndex: last,
+	}
+	for _, f := range n.followers {
+		fs := &pb.FollowerStatus{Address: f.addr, AckedIndex: f.acked}
+		if f.err != nil {
+			fs.Error = f.err.Error()
+		}
+		res.Followers = append(res.Followers, fs)
+	}
+	return res, nil
+}
@@ -0,0 +1,337 @@
+package replication_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"dist-grpc/internal/harnesstest"
+	"dist-grpc/pkg/catalog"
+	"dist-grpc/pkg/harness"
+	pb "dist-grpc/pkg/proto"
+	"dist-grpc/pkg/replication"
+	"dist-grpc/pkg/server"
+)
+
+const (
+	replicaCount     = 3
+	replicaHeartbeat = 50 * time.Millisecond
+	replicaAckWait   = 300 * time.Millisecond
+	syncTimeout      = 2 * time.Second
+)
+
+// replicaSet runs replicas that reach each other through links which can be
+// cut, as if a replica crashed or was partitioned away.
+type replicaSet struct {
+	replicas []*harness.Harness
+	nodes    []*replication.Node
+	// stores are the catalogs under the nodes, which take writes that
+	// bypass replication.
+	stores []catalog.Store
+
+	mu       sync.Mutex
+	isolated map[string]bool
+}
+
+func replicaName(i int) string {
+	return fmt.Sprintf("replica-%d", i)
+}
+
+// startReplicas starts replicaCount replicas, the first leading the others
+// with the fruits catalog while the followers start empty.
+func startReplicas(t *testing.T, minAcks int) *replicaSet {
+	t.Helper()
+	rs := &replicaSet{isolated: make(map[string]bool)}
+	for i := 0; i < replicaCount; i++ {
+		var store catalog.Store = catalog.NewMemoryStore(nil)
+		if i == 0 {
+			store = harness.NewFakeStore(harness.Fruits...)
+		}
+		node := replication.NewNode(store, replication.Config{
+			Self:       replicaName(i),
+			MinAcks:    minAcks,
+			AckTimeout: replicaAckWait,
+			Heartbeat:  replicaHeartbeat,
+			Dial:       rs.dialer(replicaName(i)),
+		})
+		h, err := harness.StartReplica(node, server.Config{})
+		if err != nil {
+			node.Close()
+			t.Fatal(err)
+		}
+		t.Cleanup(h.Close)
+		t.Cleanup(node.Close)
+		rs.mu.Lock()
+		rs.nodes = append(rs.nodes, node)
+		rs.stores = append(rs.stores, store)
+		rs.replicas = append(rs.replicas, h)
+		rs.mu.Unlock()
+	}
+	var followers []string
+	for i := 1; i < replicaCount; i++ {
+		followers = append(followers, replicaName(i))
+	}
+	rs.nodes[0].Lead(followers)
+	return rs
+}
+
+// isolate cuts the replica off from the others, or reconnects it.
+func (rs *replicaSet) isolate(i int, cut bool) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.isolated[replicaName(i)] = cut
+}
+
+func (rs *replicaSet) dialer(from string) func(addr string) (pb.ReplicationClient, error) {
+	return func(addr string) (pb.ReplicationClient, error) {
+		rs.mu.Lock()
+		defer rs.mu.Unlock()
+		for i, h := range rs.replicas {
+			if replicaName(i) == addr {
+				return &link{rs: rs, from: from, to: addr, client: h.Replication}, nil
+			}
+		}
+		return nil, fmt.Errorf("no replica %s", addr)
+	}
+}
+
+// link is the Replication client of one replica for another, failing while
+// either of them is isolated.
+type link struct {
+	rs       *replicaSet
+	from, to string
+	client   pb.ReplicationClient
+}
+
+func (l *link) check() error {
+	l.rs.mu.Lock()
+	defer l.rs.mu.Unlock()
+	if l.rs.isolated[l.from] || l.rs.isolated[l.to] {
+		return status.Errorf(codes.Unavailable, "%s cannot reach %s", l.from, l.to)
+	}
+	return nil
+}
+
+func (l *link) Append(ctx context.Context, in *pb.AppendRequest, opts ...grpc.CallOption) (*pb.AppendResponse, error) {
+	if err := l.check(); err != nil {
+		return nil, err
+	}
+	return l.client.Append(ctx, in, opts...)
+}
+
+func (l *link) InstallSnapshot(ctx context.Context, in *pb.Snapshot, opts ...grpc.CallOption) (*pb.AppendResponse, error) {
+	if err := l.check(); err != nil {
+		return nil, err
+	}
+	return l.client.InstallSnapshot(ctx, in, opts...)
+}
+
+func (l *link) Promote(ctx context.Context, in *pb.PromoteRequest, opts ...grpc.CallOption) (*pb.ReplicationStatus, error) {
+	if err := l.check(); err != nil {
+		return nil, err
+	}
+	return l.client.Promote(ctx, in, opts...)
+}
+
+func (l *link) Status(ctx context.Context, in *pb.StatusRequest, opts ...grpc.CallOption) (*pb.ReplicationStatus, error) {
+	if err := l.check(); err != nil {
+		return nil, err
+	}
+	return l.client.Status(ctx, in, opts...)
+}
+
+// waitSynced waits for the replicas to hold the catalog of the leader.
+func (rs *replicaSet) waitSynced(t *testing.T, leader int, replicas ...int) {
+	t.Helper()
+	deadline := time.Now().Add(syncTimeout)
+	for {
+		want := harness.Catalog(rs.replicas[leader].Store)
+		synced := true
+		for _, i := range replicas {
+			if !slices.Equal(harness.Catalog(rs.replicas[i].Store), want) {
+				synced = false
+			}
+		}
+		if synced {
+			return
+		}
+		if time.Now().After(deadline) {
+			for _, i := range replicas {
+				if got := harness.Catalog(rs.replicas[i].Store); !slices.Equal(got, want) {
+					t.Fatalf("%s holds %v, want %v", replicaName(i), got, want)
+				}
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func addOrder(t *testing.T, h *harness.Harness, name string) *pb.Order {
+	t.Helper()
+	order, err := h.AddOrder(name)
+	if err != nil {
+		t.Fatalf("add %q: %v", name, err)
+	}
+	return order
+}
+
+// TestShip checks that followers start from a snapshot of the leader and
+// apply every later write, serving reads of it. Writes wait for both
+// followers, so that their acks are known when they return.
+func TestShip(t *testing.T) {
+	rs := startReplicas(t, replicaCount-1)
+	rs.waitSynced(t, 0, 1, 2)
+
+	ctx := context.Background()
+	leader := rs.replicas[0].Client
+	added := addOrder(t, rs.replicas[0], "dragon fruit")
+	if _, err := leader.UpdateOrder(ctx, &pb.Order{Id: "3", Name: "blood orange"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := leader.DeleteOrder(ctx, &pb.OrderID{Id: "2"}); err != nil {
+		t.Fatal(err)
+	}
+	rs.waitSynced(t, 0, 1, 2)
+
+	res, err := rs.replicas[2].Client.GetOrderUnary(ctx, &pb.Request{Query: "fruit"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	harnesstest.ExpectResults(t, res.GetResults(), "dragon fruit")
+	got, err := rs.replicas[1].Client.GetOrderByID(ctx, &pb.OrderID{Id: added.GetId()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.GetCreated().AsTime().Equal(added.GetCreated().AsTime()) || got.GetStatus() != added.GetStatus() {
+		t.Fatalf("follower holds %v, leader added %v", got, added)
+	}
+
+	st, err := rs.replicas[0].Replication.Status(ctx, &pb.StatusRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.GetRole() != string(replication.Leader) || st.GetLastIndex() != 3 {
+		t.Fatalf("leader status %v, want leader at index 3", st)
+	}
+	for _, f := range st.GetFollowers() {
+		if f.GetAckedIndex() != 3 {
+			t.Fatalf("follower %s acked %d, want 3", f.GetAddress(), f.GetAckedIndex())
+		}
+	}
+}
+
+// TestFollowerWrite checks that followers refuse writes, pointing at the
+// leader, and leave their catalog alone.
+func TestFollowerWrite(t *testing.T) {
+	rs := startReplicas(t, 0)
+	rs.waitSynced(t, 0, 1, 2)
+
+	ctx := context.Background()
+	follower := rs.replicas[1].Client
+	_, err := rs.replicas[1].AddOrder("dragon fruit")
+	harnesstest.ExpectCode(t, err, codes.FailedPrecondition)
+	if !strings.Contains(err.Error(), replicaName(0)) {
+		t.Fatalf("error %q does not name the leader", err)
+	}
+	_, err = follower.UpdateOrder(ctx, &pb.Order{Id: "1", Name: "crab apple"})
+	harnesstest.ExpectCode(t, err, codes.FailedPrecondition)
+	_, err = follower.DeleteOrder(ctx, &pb.OrderID{Id: "1"})
+	harnesstest.ExpectCode(t, err, codes.FailedPrecondition)
+	rs.waitSynced(t, 0, 1, 2)
+}
+
+// TestMinAcks checks that a leader requiring every follower fails writes
+// while one is out of reach, and recovers once it is back.
+func TestMinAcks(t *testing.T) {
+	rs := startReplicas(t, replicaCount-1)
+	rs.waitSynced(t, 0, 1, 2)
+
+	addOrder(t, rs.replicas[0], "dragon fruit")
+	rs.isolate(2, true)
+	_, err := rs.replicas[0].AddOrder("star fruit")
+	harnesstest.ExpectCode(t, err, codes.Unavailable)
+	rs.isolate(2, false)
+	addOrder(t, rs.replicas[0], "passion fruit")
+	// The write that failed was still applied by the leader, and shipped.
+	rs.waitSynced(t, 0, 1, 2)
+}
+
+// TestCatchUp checks that a follower cut off while writes go on catches up
+// once it is reachable again.
+func TestCatchUp(t *testing.T) {
+	rs := startReplicas(t, 0)
+	rs.waitSynced(t, 0, 1, 2)
+
+	rs.isolate(2, true)
+	for _, name := range []string{"dragon fruit", "star fruit", "passion fruit"} {
+		addOrder(t, rs.replicas[0], name)
+	}
+	rs.waitSynced(t, 0, 1)
+	if len(rs.replicas[2].Store.List()) != len(harness.Fruits) {
+		t.Fatalf("isolated follower holds %v", harness.Catalog(rs.replicas[2].Store))
+	}
+	rs.isolate(2, false)
+	rs.waitSynced(t, 0, 1, 2)
+}
+
+// TestDiverged checks that a follower whose catalog no longer takes the
+// shipped writes is brought back in line with a snapshot.
+func TestDiverged(t *testing.T) {
+	rs := startReplicas(t, 0)
+	rs.waitSynced(t, 0, 1, 2)
+
+	if err := rs.stores[2].Delete("3"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rs.replicas[0].Client.UpdateOrder(context.Background(), &pb.Order{Id: "3", Name: "blood orange"}); err != nil {
+		t.Fatal(err)
+	}
+	addOrder(t, rs.replicas[0], "dragon fruit")
+	rs.waitSynced(t, 0, 1, 2)
+}
+
+// TestPromote promotes a follower while the leader is cut off. The former
+// leader steps down once it is reachable again, dropping the writes it took
+// alone.
+func TestPromote(t *testing.T) {
+	rs := startReplicas(t, 0)
+	rs.waitSynced(t, 0, 1, 2)
+	addOrder(t, rs.replicas[0], "dragon fruit")
+	rs.waitSynced(t, 0, 1, 2)
+
+	ctx := context.Background()
+	rs.isolate(0, true)
+	// Appends already on their way would tell the leader of the new epoch.
+	time.Sleep(2 * replicaHeartbeat)
+	st, err := rs.replicas[1].Replication.Promote(ctx, &pb.PromoteRequest{Followers: []string{replicaName(0), replicaName(2)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.GetRole() != string(replication.Leader) || st.GetEpoch() != 2 {
+		t.Fatalf("promoted status %v, want leader of epoch 2", st)
+	}
+	addOrder(t, rs.replicas[1], "star fruit")
+	rs.waitSynced(t, 1, 2)
+	// Cut off, the former leader still takes writes nobody else sees.
+	addOrder(t, rs.replicas[0], "lost fruit")
+
+	rs.isolate(0, false)
+	rs.waitSynced(t, 1, 0, 2)
+	_, err = rs.replicas[0].AddOrder("passion fruit")
+	harnesstest.ExpectCode(t, err, codes.FailedPrecondition)
+	st, err = rs.replicas[0].Replication.Status(ctx, &pb.StatusRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.GetRole() != string(replication.Follower) || st.GetEpoch() != 2 || st.GetLeader() != replicaName(1) {
+		t.Fatalf("former leader status %v, want follower of %s in epoch 2", st, replicaName(1))
+	}
+}
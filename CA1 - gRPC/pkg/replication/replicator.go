@@ -0,0 +1,126 @@
+package replication
+
+import (
+	"context"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"dist-grpc/pkg/catalog"
+	pb "dist-grpc/pkg/proto"
+)
+
+// follower is the progress of the log shipping to one follower.
+type follower struct {
+	addr string
+	// next is the index of the next entry to send, and acked the last one
+	// the follower has.
+	next     uint64
+	acked    uint64
+	snapshot bool
+	err      error
+}
+
+// replicate ships the log of the epoch to the follower until the node stops
+// leading it, sending a snapshot whenever the follower cannot continue its
+// own log with the entries.
+func (n *Node) replicate(ctx context.Context, f *follower, epoch uint64) {
+	for ctx.Err() == nil {
+		n.mu.Lock()
+		entries, ok := n.log.from(f.next)
+		prevIndex := f.next - 1
+		prevEpoch, _ := n.log.epochAt(prevIndex)
+		snapshot := f.snapshot || !ok
+		changed := n.changed
+		n.mu.Unlock()
+
+		var resp *pb.AppendResponse
+		client, err := n.client(f.addr)
+		if err == nil {
+			callCtx, cancel := context.WithTimeout(ctx, n.config.Heartbeat+n.config.AckTimeout)
+			if snapshot {
+				resp, err = n.sendSnapshot(callCtx, client, epoch)
+			} else {
+				resp, err = client.Append(callCtx, &pb.AppendRequest{
+					Epoch:     epoch,
+					Leader:    n.config.Self,
+					PrevIndex: prevIndex,
+					PrevEpoch: prevEpoch,
+					Entries:   entries,
+				})
+			}
+			cancel()
+		}
+		n.mu.Lock()
+		switch {
+		case ctx.Err() != nil:
+			n.mu.Unlock()
+			return
+		case status.Code(err) == codes.Aborted:
+			log.Warn("Follower diverged, sending a snapshot", "follower", f.addr, "err", err)
+			f.snapshot = true
+			n.mu.Unlock()
+			continue
+		case err != nil:
+			if f.err == nil {
+				log.Warn("Failed to replicate", "follower", f.addr, "err", err)
+			}
+			f.err = err
+		case resp.GetEpoch() > epoch:
+			n.follow(resp.GetEpoch(), "")
+			n.mu.Unlock()
+			return
+		case resp.GetSuccess():
+			if f.err != nil || snapshot {
+				log.Info("Follower caught up", "follower", f.addr, "index", resp.GetLastIndex())
+			}
+			f.err, f.snapshot = nil, false
+			f.acked, f.next = resp.GetLastIndex(), resp.GetLastIndex()+1
+			n.broadcast()
+		default:
+			// The follower resumes from its last entry when the log still
+			// holds it, and from a snapshot otherwise.
+			last, _ := n.log.last()
+			e, ok := n.log.epochAt(resp.GetLastIndex())
+			if ok && e == resp.GetLastEpoch() && resp.GetLastIndex() <= last {
+				f.next = resp.GetLastIndex() + 1
+			} else {
+				f.snapshot = true
+			}
+			n.mu.Unlock()
+			continue
+		}
+		n.mu.Unlock()
+		if err == nil && len(entries) > 0 {
+			continue
+		}
+		// A failing follower is retried on the next heartbeat rather than
+		// on every write.
+		if err != nil {
+			changed = nil
+		}
+		select {
+		case <-ctx.Done():
+		case <-changed:
+		case <-time.After(n.config.Heartbeat):
+		}
+	}
+}
+
+// sendSnapshot sends the catalog as of the last entry of the log.
+func (n *Node) sendSnapshot(ctx context.Context, client pb.ReplicationClient, epoch uint64) (*pb.AppendResponse, error) {
+	n.writeMu.Lock()
+	orders := n.store.List()
+	n.mu.Lock()
+	index, indexEpoch := n.log.last()
+	n.mu.Unlock()
+	n.writeMu.Unlock()
+
+	req := &pb.Snapshot{Epoch: epoch, Leader: n.config.Self, Index: index, IndexEpoch: indexEpoch}
+	for _, order := range orders {
+		req.Orders = append(req.Orders, catalog.ToProto(order))
+	}
+	return client.InstallSnapshot(ctx, req)
+}
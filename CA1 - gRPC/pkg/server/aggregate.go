@@ -0,0 +1,168 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"dist-grpc/pkg/matcher"
+	"dist-grpc/pkg/pagination"
+	pb "dist-grpc/pkg/proto"
+	"dist-grpc/pkg/utils"
+)
+
+// queryMatches are the matches of one request of a client stream, or the
+// reason it was rejected.
+type queryMatches struct {
+	matches
+	index  int
+	req    *pb.Request
+	reason string
+}
+
+type resultCount struct {
+	result  matcher.Result
+	queries []int32
+}
+
+func (q queryMatches) field() string {
+	return fmt.Sprintf("requests[%d].query", q.index)
+}
+
+func valid(queries []queryMatches) []queryMatches {
+	var res []queryMatches
+	for _, q := range queries {
+		if q.reason == "" {
+			res = append(res, q)
+		}
+	}
+	return res
+}
+
+// aggregate combines the matches of a client stream as asked for by its
+// first request, whose paging options apply to the combined result.
+func aggregate(first *pb.Request, queries []queryMatches) (*pb.Response, error) {
+	var requests []*pb.Request
+	var lists [][]matcher.Result
+	var missing []string
+	for _, q := range valid(queries) {
+		requests = append(requests, q.req)
+		lists = append(lists, q.results)
+		missing = append(missing, q.missing...)
+	}
+	key := first.GetAggregation().String() + "\n" + pageKey(requests...)
+
+	var resp *pb.Response
+	switch first.GetAggregation() {
+	case pb.Aggregation_AGGREGATION_GROUPED:
+		resp = groupedResponse(first, queries)
+	case pb.Aggregation_AGGREGATION_COUNTS:
+		page, err := paginate(first, key, countResults(valid(queries)))
+		if err != nil {
+			return nil, err
+		}
+		resp = countsResponse(first, page)
+	case pb.Aggregation_AGGREGATION_INTERSECTION:
+		page, err := paginate(first, key, matcher.Intersect(lists...))
+		if err != nil {
+			return nil, err
+		}
+		resp = newResponse(first, page)
+	default:
+		var all []matcher.Result
+		for _, list := range lists {
+			all = append(all, list...)
+		}
+		page, err := paginate(first, key, matcher.Merge(all))
+		if err != nil {
+			return nil, err
+		}
+		resp = newResponse(first, page)
+	}
+
+	var violations []*errdetails.BadRequest_FieldViolation
+	for _, q := range queries {
+		if q.reason != "" {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: q.field(), Description: q.reason})
+		}
+	}
+	if len(violations) > 0 {
+		resp.Status = invalidQuery(violations...).Proto()
+	}
+	return matches{missing: utils.RemoveDuplicates(missing)}.markPartial(resp), nil
+}
+
+// groupedResponse answers every query in its own group, in the order they
+// were sent, each capped by the max_results of its own request.
+func groupedResponse(first *pb.Request, queries []queryMatches) *pb.Response {
+	resp := &pb.Response{RequestId: first.GetRequestId(), Timestamp: timestamppb.Now()}
+	for _, q := range queries {
+		group := &pb.QueryMatches{
+			Index:     int32(q.index),
+			Query:     q.req.GetQuery(),
+			RequestId: q.req.GetRequestId(),
+		}
+		if q.reason != "" {
+			group.Status = status.New(codes.InvalidArgument, q.reason).Proto()
+		}
+		results := q.results
+		if n := int(q.req.GetMaxResults()); n > 0 && n < len(results) {
+			results = results[:n]
+		}
+		group.Results = matcher.Names(results)
+		group.Scores = matcher.Scores(results)
+		resp.TotalCount += int32(len(results))
+		resp.Groups = append(resp.Groups, group)
+	}
+	return resp
+}
+
+// countResults lists every matched order with the queries that matched it,
+// the most matched first and then by best score.
+func countResults(queries []queryMatches) []resultCount {
+	index := make(map[string]int)
+	var counts []resultCount
+	for _, q := range queries {
+		for _, r := range q.results {
+			j, ok := index[r.Order.ID]
+			if !ok {
+				j = len(counts)
+				index[r.Order.ID] = j
+				counts = append(counts, resultCount{result: r})
+			}
+			counts[j].result.Score = max(counts[j].result.Score, r.Score)
+			counts[j].queries = append(counts[j].queries, int32(q.index))
+		}
+	}
+	sort.SliceStable(counts, func(i, j int) bool {
+		if len(counts[i].queries) != len(counts[j].queries) {
+			return len(counts[i].queries) > len(counts[j].queries)
+		}
+		return counts[i].result.Score > counts[j].result.Score
+	})
+	return counts
+}
+
+func countsResponse(first *pb.Request, page pagination.Page[resultCount]) *pb.Response {
+	resp := &pb.Response{
+		RequestId:     first.GetRequestId(),
+		Timestamp:     timestamppb.Now(),
+		NextPageToken: page.NextPageToken,
+		TotalCount:    int32(page.TotalCount),
+	}
+	for _, c := range page.Items {
+		resp.Results = append(resp.Results, c.result.Order.Name)
+		resp.Scores = append(resp.Scores, c.result.Score)
+		resp.Counts = append(resp.Counts, &pb.ResultCount{
+			Result:  c.result.Order.Name,
+			Count:   int32(len(c.queries)),
+			Queries: c.queries,
+			Score:   c.result.Score,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,75 @@
+package server_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"dist-grpc/internal/harnesstest"
+	pb "dist-grpc/pkg/proto"
+)
+
+// streamAggregated streams "apple", "red" and an invalid query under the
+// aggregation and returns the response.
+func streamAggregated(t *testing.T, aggregation pb.Aggregation) *pb.Response {
+	t.Helper()
+	h := startDefault(t)
+
+	stream, err := h.Client.GetOrderClientStream(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, query := range []string{"apple", "red", "bad!"} {
+		req := &pb.Request{Query: query, Aggregation: aggregation}
+		if err := stream.Send(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GetStatus() == nil {
+		t.Fatal("response has no status for the invalid query")
+	}
+	return resp
+}
+
+func TestAggregateUnion(t *testing.T) {
+	resp := streamAggregated(t, pb.Aggregation_AGGREGATION_UNION)
+	harnesstest.ExpectResults(t, resp.GetResults(), "apple", "red apple", "green apple")
+}
+
+func TestAggregateIntersection(t *testing.T) {
+	resp := streamAggregated(t, pb.Aggregation_AGGREGATION_INTERSECTION)
+	harnesstest.ExpectResults(t, resp.GetResults(), "red apple")
+}
+
+func TestAggregateGrouped(t *testing.T) {
+	resp := streamAggregated(t, pb.Aggregation_AGGREGATION_GROUPED)
+	want := map[int32][]string{0: {"apple", "red apple", "green apple"}, 1: {"red apple"}, 2: nil}
+	if len(resp.GetGroups()) != len(want) {
+		t.Fatalf("got %d groups, want %d", len(resp.GetGroups()), len(want))
+	}
+	for _, group := range resp.GetGroups() {
+		if !slices.Equal(group.GetResults(), want[group.GetIndex()]) {
+			t.Fatalf("group %d has results %v, want %v", group.GetIndex(), group.GetResults(), want[group.GetIndex()])
+		}
+	}
+	if resp.GetGroups()[2].GetStatus() == nil {
+		t.Fatal("invalid query has no status in its group")
+	}
+}
+
+func TestAggregateCounts(t *testing.T) {
+	counts := streamAggregated(t, pb.Aggregation_AGGREGATION_COUNTS).GetCounts()
+	if len(counts) != 3 {
+		t.Fatalf("got %d counts, want 3", len(counts))
+	}
+	if counts[0].GetResult() != "red apple" || !slices.Equal(counts[0].GetQueries(), []int32{0, 1}) {
+		t.Fatalf("got first count %v, want red apple matched by queries 0 and 1", counts[0])
+	}
+	if last := counts[2]; last.GetResult() != "green apple" || !slices.Equal(last.GetQueries(), []int32{0}) {
+		t.Fatalf("got last count %v, want green apple matched by query 0", last)
+	}
+}
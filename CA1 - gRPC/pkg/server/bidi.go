@@ -0,0 +1,107 @@
+package server
+
+import (
+	"context"
+	"io"
+
+	"github.com/charmbracelet/log"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"dist-grpc/pkg/matcher"
+	pb "dist-grpc/pkg/proto"
+	"dist-grpc/pkg/utils"
+)
+
+type bidiResult struct {
+	resp *pb.Response
+	err  error
+}
+
+// failedResponse reports the error of one query while the stream goes on.
+func failedResponse(req *pb.Request, err error) *pb.Response {
+	return &pb.Response{
+		RequestId: req.GetRequestId(),
+		Timestamp: timestamppb.Now(),
+		Status:    status.Convert(err).Proto(),
+	}
+}
+
+func (s *Server) answer(ctx context.Context, req *pb.Request) bidiResult {
+	log.Debug("Received request", "query", req.GetQuery(), "mode", req.GetMode(), "request_id", req.GetRequestId())
+	if err := validateQuery(req.GetQuery()); err != nil {
+		return bidiResult{resp: failedResponse(req, err)}
+	}
+	m, err := s.search(ctx, req)
+	if err != nil {
+		return bidiResult{err: err}
+	}
+	log.Debug("Matched orders", "orders", utils.ToString(matcher.Names(m.results)))
+	page, err := paginate(req, pageKey(req), m.results)
+	if err != nil {
+		return bidiResult{resp: failedResponse(req, err)}
+	}
+	return bidiResult{resp: m.markPartial(newResponse(req, page))}
+}
+
+// GetOrderBiDiStream matches up to BiDiWorkers requests of the stream at a
+// time. A worker slot is only freed once its response has been sent, so a
+// client that stops reading stops the server from taking in more requests
+// rather than making it buffer their responses.
+func (s *Server) GetOrderBiDiStream(stream pb.OrderManagement_GetOrderBiDiStreamServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	workers := max(s.config.BiDiWorkers, 1)
+	slots := make(chan struct{}, workers)
+	// pending has an entry per request in arrival order, holding the channel
+	// its result is delivered on. Unordered streams deliver every result on
+	// one shared channel, so each entry takes whichever result is ready first.
+	pending := make(chan chan bidiResult, workers)
+	shared := make(chan bidiResult, workers)
+
+	var recvErr error
+	go func() {
+		defer close(pending)
+		for {
+			req, err := stream.Recv()
+			if err == io.EOF {
+				log.Debug("Received EOF, closing stream")
+				return
+			}
+			if err != nil {
+				recvErr = contextError(ctx, err)
+				return
+			}
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			out := shared
+			if !s.config.BiDiUnordered {
+				out = make(chan bidiResult, 1)
+			}
+			pending <- out
+			go func() {
+				out <- s.answer(ctx, req)
+			}()
+		}
+	}()
+
+	for out := range pending {
+		var r bidiResult
+		select {
+		case r = <-out:
+		case <-ctx.Done():
+			return contextError(ctx, nil)
+		}
+		if r.err != nil {
+			return r.err
+		}
+		if err := stream.Send(r.resp); err != nil {
+			return contextError(ctx, err)
+		}
+		<-slots
+	}
+	return recvErr
+}
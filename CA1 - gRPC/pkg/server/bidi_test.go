@@ -0,0 +1,75 @@
+package server_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"testing"
+
+	"dist-grpc/pkg/harness"
+	pb "dist-grpc/pkg/proto"
+	"dist-grpc/pkg/server"
+)
+
+// receiveOrdering sends a slow query followed by fast ones on a stream
+// served by several workers, and returns the request ids sent and those of
+// the responses in the order they were received.
+func receiveOrdering(t *testing.T, unordered bool) (sent, received []string) {
+	t.Helper()
+	h, _ := startLinear(t, server.Config{BiDiWorkers: 4, BiDiUnordered: unordered})
+
+	ctx, cancel := context.WithTimeout(context.Background(), harness.HandledTimeout)
+	defer cancel()
+	stream, err := h.Client.GetOrderBiDiStream(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slow := &pb.Request{Query: slowQuery.Query, Mode: slowQuery.Mode, RequestId: "slow"}
+	if err := stream.Send(slow); err != nil {
+		t.Fatal(err)
+	}
+	sent = []string{"slow"}
+	for i := 1; i <= 3; i++ {
+		id := fmt.Sprintf("fast-%d", i)
+		req := &pb.Request{Query: fmt.Sprintf("order %d", i), Mode: pb.MatchMode_MATCH_MODE_EXACT, RequestId: id}
+		if err := stream.Send(req); err != nil {
+			t.Fatal(err)
+		}
+		sent = append(sent, id)
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return sent, received
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id := resp.GetRequestId(); id != "slow" && !slices.Equal(resp.GetResults(), []string{"order " + id[len("fast-"):]}) {
+			t.Fatalf("response to %s has results %v", id, resp.GetResults())
+		}
+		received = append(received, resp.GetRequestId())
+	}
+}
+
+// TestBiDiOrdered checks that ordered streams answer the slow query first.
+func TestBiDiOrdered(t *testing.T) {
+	sent, received := receiveOrdering(t, false)
+	if !slices.Equal(received, sent) {
+		t.Fatalf("received %v, want %v", received, sent)
+	}
+}
+
+// TestBiDiUnordered checks that unordered streams let the fast answers
+// overtake the slow one.
+func TestBiDiUnordered(t *testing.T) {
+	sent, received := receiveOrdering(t, true)
+	if len(received) != len(sent) || received[len(received)-1] != "slow" {
+		t.Fatalf("received %v, want the slow response last", received)
+	}
+}
@@ -0,0 +1,126 @@
+package server_test
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+
+	"dist-grpc/internal/harnesstest"
+	"dist-grpc/pkg/catalog"
+	"dist-grpc/pkg/harness"
+	"dist-grpc/pkg/matcher"
+	pb "dist-grpc/pkg/proto"
+	"dist-grpc/pkg/server"
+)
+
+// largeCatalog is big enough that scanning it for a fuzzy query takes far
+// longer than the deadlines below.
+const largeCatalog = 200000
+
+// slowQuery is a fuzzy query that every order has to be scored against.
+var slowQuery = &pb.Request{Query: "zzzz qqqq", Mode: pb.MatchMode_MATCH_MODE_FUZZY}
+
+func numberedOrders(n int) []catalog.Order {
+	orders := make([]catalog.Order, n)
+	for i := range orders {
+		orders[i] = catalog.Order{ID: strconv.Itoa(i), Name: fmt.Sprintf("order %d", i)}
+	}
+	return orders
+}
+
+// startLinear serves a large catalog with the scanning matcher and returns
+// how long a full scan for the slow query takes.
+func startLinear(t *testing.T, config server.Config) (*harness.Harness, time.Duration) {
+	t.Helper()
+	store := catalog.NewMemoryStore(numberedOrders(largeCatalog))
+	m := matcher.NewLinear(store)
+	start := time.Now()
+	m.Match(slowQuery.Query, matcher.Fuzzy)
+	scan := time.Since(start)
+	h, err := harness.Start(store, m, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(h.Close)
+	return h, scan
+}
+
+// expectStoppedEarly checks that the handler gave up well before a full
+// scan.
+func expectStoppedEarly(t *testing.T, handled harness.Handled, scan time.Duration) {
+	t.Helper()
+	if handled.Duration > scan/2 {
+		t.Fatalf("handler ran for %s, a full scan takes %s", handled.Duration, scan)
+	}
+}
+
+func TestCancelUnaryDeadline(t *testing.T) {
+	h, scan := startLinear(t, server.Config{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), scan/10)
+	defer cancel()
+	_, err := h.Client.GetOrderUnary(ctx, slowQuery)
+	harnesstest.ExpectCode(t, err, codes.DeadlineExceeded)
+	expectStoppedEarly(t, harnesstest.ExpectHandled(t, h, codes.DeadlineExceeded), scan)
+}
+
+func TestCancelServerStream(t *testing.T) {
+	store := catalog.NewMemoryStore(numberedOrders(largeCatalog))
+	h, err := harness.Start(store, matcher.New(store), server.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := h.Client.GetOrderServerStream(ctx, &pb.Request{Query: "order"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	harnesstest.ExpectHandled(t, h, codes.Canceled)
+}
+
+func TestCancelClientStream(t *testing.T) {
+	h, scan := startLinear(t, server.Config{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := h.Client.GetOrderClientStream(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := stream.Send(slowQuery); err != nil {
+			t.Fatal(err)
+		}
+	}
+	time.Sleep(scan / 10)
+	cancel()
+	expectStoppedEarly(t, harnesstest.ExpectHandled(t, h, codes.Canceled), 3*scan)
+}
+
+func TestCancelBiDiStream(t *testing.T) {
+	h, scan := startLinear(t, server.Config{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := h.Client.GetOrderBiDiStream(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.Send(slowQuery); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(scan / 10)
+	cancel()
+	expectStoppedEarly(t, harnesstest.ExpectHandled(t, h, codes.Canceled), scan)
+}
@@ -0,0 +1,190 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"dist-grpc/pkg/catalog"
+	"dist-grpc/pkg/matcher"
+	pb "dist-grpc/pkg/proto"
+)
+
+// Shard is a backend OrderManagement server holding the orders whose ids
+// hash to its position among the shards.
+type Shard struct {
+	Name   string
+	Client pb.OrderManagementClient
+}
+
+// Coordinator serves the OrderManagement service over sharded backends. It
+// answers every query pattern like a Server, with matches gathered from all
+// shards at once, and routes each order operation to the shard of its id.
+type Coordinator struct {
+	*Server
+	shards  []Shard
+	timeout time.Duration
+}
+
+// NewCoordinator returns a coordinator that waits at most timeout for the
+// shards to answer a query, leaving out those that do not.
+func NewCoordinator(shards []Shard, timeout time.Duration, config Config) *Coordinator {
+	c := &Coordinator{shards: shards, timeout: timeout}
+	c.Server = &Server{config: config, search: c.scatter}
+	return c
+}
+
+// forward passes the caller's credentials on to the shards.
+func forward(ctx context.Context) context.Context {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if token := md.Get("authorization"); len(token) > 0 {
+			return metadata.AppendToOutgoingContext(ctx, "authorization", token[0])
+		}
+	}
+	return ctx
+}
+
+func (c *Coordinator) shardOf(id string) Shard {
+	return c.shards[catalog.ShardOf(id, len(c.shards))]
+}
+
+type shardAnswer struct {
+	results []matcher.Result
+	err     error
+}
+
+// scatter sends the query to every shard and merges their matches, dropping
+// repeated names like utils.RemoveDuplicates and keeping their best score.
+// Shards that fail or do not answer within the timeout are left out and
+// reported as missing; the query only fails when no shard answered.
+func (c *Coordinator) scatter(ctx context.Context, req *pb.Request) (matches, error) {
+	shardReq := proto.Clone(req).(*pb.Request)
+	// Each shard returns its best max_results matches in one page, and the
+	// coordinator pages through the merged matches.
+	shardReq.PageSize = 0
+	shardReq.PageToken = ""
+	shardCtx, cancel := context.WithTimeout(forward(ctx), c.timeout)
+	defer cancel()
+
+	answers := make([]shardAnswer, len(c.shards))
+	var wg sync.WaitGroup
+	for i, shard := range c.shards {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := shard.Client.GetOrderUnary(shardCtx, shardReq)
+			if err != nil {
+				answers[i].err = err
+				return
+			}
+			if len(resp.GetScores()) != len(resp.GetResults()) {
+				answers[i].err = status.Errorf(codes.Internal, "shard returned %d scores for %d results", len(resp.GetScores()), len(resp.GetResults()))
+				return
+			}
+			for j, name := range resp.GetResults() {
+				// Orders are told apart by name, as shards do not return ids.
+				order := catalog.Order{ID: name, Name: name}
+				answers[i].results = append(answers[i].results, matcher.Result{Order: order, Score: resp.GetScores()[j]})
+			}
+		}()
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return matches{}, contextError(ctx, nil)
+	}
+
+	var m matches
+	var all []matcher.Result
+	var lastErr error
+	for i, answer := range answers {
+		if answer.err != nil {
+			log.Warn("Shard left out of the results", "shard", c.shards[i].Name, "code", status.Code(answer.err), "err", answer.err)
+			m.missing = append(m.missing, c.shards[i].Name)
+			lastErr = answer.err
+			continue
+		}
+		all = append(all, answer.results...)
+	}
+	if len(m.missing) == len(c.shards) {
+		return matches{}, status.Errorf(codes.Unavailable, "no shard answered: %v", lastErr)
+	}
+	m.results = matcher.Merge(all)
+	return m, nil
+}
+
+func (c *Coordinator) AddOrder(ctx context.Context, req *pb.Order) (*pb.Order, error) {
+	if err := validateOrder(req); err != nil {
+		return nil, err
+	}
+	// The id is chosen here, as it decides the shard.
+	if req.GetId() == "" {
+		req = proto.Clone(req).(*pb.Order)
+		req.Id = newOrderID()
+	}
+	return c.shardOf(req.GetId()).Client.AddOrder(forward(ctx), req)
+}
+
+func (c *Coordinator) UpdateOrder(ctx context.Context, req *pb.Order) (*pb.Order, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "order id is required")
+	}
+	return c.shardOf(req.GetId()).Client.UpdateOrder(forward(ctx), req)
+}
+
+func (c *Coordinator) DeleteOrder(ctx context.Context, req *pb.OrderID) (*pb.Order, error) {
+	return c.shardOf(req.GetId()).Client.DeleteOrder(forward(ctx), req)
+}
+
+func (c *Coordinator) GetOrderByID(ctx context.Context, req *pb.OrderID) (*pb.Order, error) {
+	return c.shardOf(req.GetId()).Client.GetOrderByID(forward(ctx), req)
+}
+
+// WatchOrders watches the query on every shard and passes on their events,
+// ending as soon as one of the shard watches ends.
+func (c *Coordinator) WatchOrders(req *pb.Request, stream pb.OrderManagement_WatchOrdersServer) error {
+	if err := validateQuery(req.GetQuery()); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(forward(stream.Context()))
+	defer cancel()
+
+	events := make(chan *pb.OrderEvent)
+	errc := make(chan error, len(c.shards))
+	for _, shard := range c.shards {
+		watch, err := shard.Client.WatchOrders(ctx, req)
+		if err != nil {
+			return status.Errorf(status.Code(err), "failed to watch shard %s: %v", shard.Name, err)
+		}
+		go func() {
+			for {
+				event, err := watch.Recv()
+				if err != nil {
+					errc <- err
+					return
+				}
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	for {
+		select {
+		case event := <-events:
+			if err := stream.Send(event); err != nil {
+				return contextError(stream.Context(), err)
+			}
+		case err := <-errc:
+			return contextError(stream.Context(), status.Convert(err).Err())
+		}
+	}
+}
@@ -0,0 +1,391 @@
+package server_test
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	"dist-grpc/internal/harnesstest"
+	"dist-grpc/pkg/catalog"
+	"dist-grpc/pkg/harness"
+	"dist-grpc/pkg/matcher"
+	"dist-grpc/pkg/orderclient"
+	pb "dist-grpc/pkg/proto"
+	"dist-grpc/pkg/server"
+	"dist-grpc/pkg/utils"
+)
+
+const (
+	shardCount   = 3
+	shardTimeout = 200 * time.Millisecond
+)
+
+// shardedFruits repeats some names under other ids, which the coordinator
+// merges like a single server's results passed through RemoveDuplicates.
+var shardedFruits = append(slices.Clone(harness.Fruits), "apple", "banana", "apple pie", "red apple", "pear", "grape", "apple")
+
+type sharded struct {
+	shards      []*harness.Harness
+	coordinator *harness.Harness
+}
+
+// startSharded splits shardedFruits over shardCount harnesses and
+// coordinates them. Shard slow, unless it is -1, takes a second to scan its
+// catalog for every query.
+func startSharded(t *testing.T, slow int) *sharded {
+	t.Helper()
+	s := &sharded{}
+	var shards []server.Shard
+	orders := harness.NewFakeStore(shardedFruits...).List()
+	for i := 0; i < shardCount; i++ {
+		store := &harness.FakeStore{MemoryStore: catalog.NewMemoryStore(catalog.Partition(orders, i, shardCount))}
+		m := matcher.New(store)
+		if i == slow {
+			store.ListDelay = time.Second
+			m = matcher.NewLinear(store)
+		}
+		h, err := harness.Start(store, m, server.Config{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(h.Close)
+		s.shards = append(s.shards, h)
+		shards = append(shards, server.Shard{Name: fmt.Sprintf("shard-%d", i), Client: h.Client})
+	}
+	coordinator, err := harness.Serve(server.NewCoordinator(shards, shardTimeout, server.Config{BiDiWorkers: 4}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(coordinator.Close)
+	s.coordinator = coordinator
+	return s
+}
+
+// bestScores maps every result to its best score.
+func bestScores(res *pb.Response) map[string]float64 {
+	scores := make(map[string]float64)
+	for i, name := range res.GetResults() {
+		scores[name] = max(scores[name], res.GetScores()[i])
+	}
+	return scores
+}
+
+func descending(a, b float64) int {
+	return cmp.Compare(b, a)
+}
+
+// TestCoordinatorScatterGather compares the coordinator with a single server
+// holding the whole catalog.
+func TestCoordinatorScatterGather(t *testing.T) {
+	s := startSharded(t, -1)
+	store := harness.NewFakeStore(shardedFruits...)
+	single, err := harness.Start(store, matcher.New(store), server.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer single.Close()
+
+	ctx := context.Background()
+	for _, req := range []*pb.Request{
+		{Query: "apple"},
+		{Query: "an"},
+		{Query: "aple", Mode: pb.MatchMode_MATCH_MODE_FUZZY},
+		{Query: "apple", MaxResults: 2},
+	} {
+		whole := &pb.Request{Query: req.GetQuery(), Mode: req.GetMode()}
+		want, err := single.Client.GetOrderUnary(ctx, whole)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := s.coordinator.Client.GetOrderUnary(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.GetPartial() {
+			t.Fatalf("%q: got partial results from healthy shards", req.GetQuery())
+		}
+		if len(utils.RemoveDuplicates(got.GetResults())) != len(got.GetResults()) {
+			t.Fatalf("%q: duplicated results %v", req.GetQuery(), got.GetResults())
+		}
+		if !slices.IsSortedFunc(got.GetScores(), descending) {
+			t.Fatalf("%q: scores %v are not in descending order", req.GetQuery(), got.GetScores())
+		}
+		if req.GetMaxResults() == 0 {
+			if !maps.Equal(bestScores(got), bestScores(want)) {
+				t.Fatalf("%q: got %v, want %v", req.GetQuery(), got.GetResults(), utils.RemoveDuplicates(want.GetResults()))
+			}
+			continue
+		}
+		// Names tied at the cut may come from any shard, so only the
+		// scores of the best distinct names are compared.
+		var best []float64
+		for _, score := range bestScores(want) {
+			best = append(best, score)
+		}
+		slices.SortFunc(best, descending)
+		if !slices.Equal(got.GetScores(), best[:req.GetMaxResults()]) {
+			t.Fatalf("%q: got scores %v, want the best %d of %v", req.GetQuery(), got.GetScores(), req.GetMaxResults(), best)
+		}
+	}
+}
+
+// TestCoordinatorPatterns checks that every pattern, pagination included,
+// gives the coordinator's merged matches.
+func TestCoordinatorPatterns(t *testing.T) {
+	s := startSharded(t, -1)
+
+	ctx := context.Background()
+	whole, err := s.coordinator.Client.GetOrderUnary(ctx, &pb.Request{Query: "apple"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := whole.GetResults()
+	client := orderclient.New(s.coordinator.Conn, orderclient.Options{PageSize: 2, CallTimeout: harness.HandledTimeout})
+
+	pages, err := client.Unary(ctx, "apple")
+	if err != nil {
+		t.Fatal(err)
+	}
+	harnesstest.ExpectResults(t, results(pages), want...)
+	var streamed []orderclient.Response
+	if err := client.ServerStream(ctx, "apple", orderclient.Collect(&streamed)); err != nil {
+		t.Fatal(err)
+	}
+	harnesstest.ExpectResults(t, results(streamed), want...)
+	var answered []orderclient.Response
+	if err := client.BiDi(ctx, queryChannel("apple"), orderclient.Collect(&answered)); err != nil {
+		t.Fatal(err)
+	}
+	if len(answered) != 1 || !slices.Equal(answered[0].GetResults(), want[:2]) {
+		t.Fatalf("bidi: got %v, want the first page of %v", answered, want)
+	}
+	res, err := orderclient.New(s.coordinator.Conn, orderclient.Options{}).ClientStream(ctx, queryChannel("apple", "apple"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	harnesstest.ExpectResults(t, res.GetResults(), want...)
+}
+
+// TestCoordinatorSlowShard answers within the shard timeout with the
+// matches of the other shards, flagged as partial.
+func TestCoordinatorSlowShard(t *testing.T) {
+	s := startSharded(t, 1)
+
+	start := time.Now()
+	res, err := s.coordinator.Client.GetOrderUnary(context.Background(), &pb.Request{Query: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 4*shardTimeout {
+		t.Fatalf("answered after %s, want about %s", elapsed, shardTimeout)
+	}
+	if !res.GetPartial() || !slices.Equal(res.GetMissingShards(), []string{"shard-1"}) {
+		t.Fatalf("got partial=%t missing %v, want shard-1 missing", res.GetPartial(), res.GetMissingShards())
+	}
+	var want []string
+	for _, i := range []int{0, 2} {
+		for _, order := range s.shards[i].Store.List() {
+			if strings.Contains(order.Name, "a") {
+				want = append(want, order.Name)
+			}
+		}
+	}
+	if got := res.GetResults(); len(got) != len(utils.RemoveDuplicates(want)) {
+		t.Fatalf("got %v, want the orders of the other shards %v", got, want)
+	}
+}
+
+func TestCoordinatorNoShard(t *testing.T) {
+	s := startSharded(t, -1)
+	for _, h := range s.shards {
+		h.Server.Stop()
+	}
+
+	_, err := s.coordinator.Client.GetOrderUnary(context.Background(), &pb.Request{Query: "apple"})
+	harnesstest.ExpectCode(t, err, codes.Unavailable)
+}
+
+// scoreless drops the scores of its shard's answers.
+type scoreless struct {
+	pb.OrderManagementClient
+}
+
+func (c scoreless) GetOrderUnary(ctx context.Context, req *pb.Request, opts ...grpc.CallOption) (*pb.Response, error) {
+	res, err := c.OrderManagementClient.GetOrderUnary(ctx, req, opts...)
+	if err == nil {
+		res.Scores = res.Scores[:len(res.Scores)/2]
+	}
+	return res, err
+}
+
+// TestCoordinatorBadShard leaves out a shard whose answer lacks scores.
+func TestCoordinatorBadShard(t *testing.T) {
+	s := startSharded(t, -1)
+	shards := make([]server.Shard, shardCount)
+	for i, h := range s.shards {
+		shards[i] = server.Shard{Name: fmt.Sprintf("shard-%d", i), Client: h.Client}
+	}
+	shards[1].Client = scoreless{shards[1].Client}
+	coordinator, err := harness.Serve(server.NewCoordinator(shards, shardTimeout, server.Config{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer coordinator.Close()
+
+	res, err := coordinator.Client.GetOrderUnary(context.Background(), &pb.Request{Query: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.GetPartial() || !slices.Equal(res.GetMissingShards(), []string{"shard-1"}) {
+		t.Fatalf("got partial=%t missing %v, want shard-1 missing", res.GetPartial(), res.GetMissingShards())
+	}
+}
+
+// TestCoordinatorRouting adds, reads and deletes orders through the
+// coordinator and checks they live on the shard of their id.
+func TestCoordinatorRouting(t *testing.T) {
+	s := startSharded(t, -1)
+
+	ctx := context.Background()
+	for i := 0; i < 6; i++ {
+		added, err := s.coordinator.Client.AddOrder(ctx, &pb.Order{Name: fmt.Sprintf("durian %d", i)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		owner := catalog.ShardOf(added.GetId(), shardCount)
+		for j, h := range s.shards {
+			if _, err := h.Store.Get(added.GetId()); (err == nil) != (j == owner) {
+				t.Fatalf("order %s found on shard %d: %t, want it on shard %d only", added.GetId(), j, err == nil, owner)
+			}
+		}
+		if _, err := s.coordinator.Client.GetOrderByID(ctx, &pb.OrderID{Id: added.GetId()}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.coordinator.Client.DeleteOrder(ctx, &pb.OrderID{Id: added.GetId()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err := s.coordinator.Client.GetOrderByID(ctx, &pb.OrderID{Id: "missing"})
+	harnesstest.ExpectCode(t, err, codes.NotFound)
+}
+
+// TestCoordinatorWatch watches through the coordinator, which passes on the
+// initial matches of every shard and then the changes made on any of them.
+func TestCoordinatorWatch(t *testing.T) {
+	s := startSharded(t, -1)
+	stream := watchQuery(t, s.coordinator, "pear")
+	expectEvents(t, stream, wantEvent{pb.EventType_EVENT_TYPE_ADDED, "pear", true})
+
+	if _, err := s.coordinator.Client.AddOrder(context.Background(), &pb.Order{Name: "pear tart"}); err != nil {
+		t.Fatal(err)
+	}
+	expectEvents(t, stream, wantEvent{pb.EventType_EVENT_TYPE_ADDED, "pear tart", false})
+}
+
+// startShardFiles serves the shards of the catalog file at path, opened as
+// the server opens them, behind a coordinator. close stops them all.
+func startShardFiles(t *testing.T, path string) (coordinator *harness.Harness, close func()) {
+	t.Helper()
+	var harnesses []*harness.Harness
+	close = func() {
+		for _, h := range harnesses {
+			h.Close()
+		}
+	}
+	var shards []server.Shard
+	for i := 0; i < shardCount; i++ {
+		store, err := catalog.OpenShard(path, i, shardCount)
+		if err != nil {
+			close()
+			t.Fatal(err)
+		}
+		h, err := harness.Start(store, matcher.New(store), server.Config{})
+		if err != nil {
+			close()
+			t.Fatal(err)
+		}
+		harnesses = append(harnesses, h)
+		shards = append(shards, server.Shard{Name: fmt.Sprintf("shard-%d", i), Client: h.Client})
+	}
+	coordinator, err := harness.Serve(server.NewCoordinator(shards, shardTimeout, server.Config{}))
+	if err != nil {
+		close()
+		t.Fatal(err)
+	}
+	harnesses = append(harnesses, coordinator)
+	return coordinator, close
+}
+
+// TestShardFilesKeepWrites writes through sharded servers of a catalog file,
+// restarts them and reads the writes back, the catalog file itself being
+// left untouched.
+func TestShardFilesKeepWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.json")
+	file, err := catalog.NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := catalog.Replace(file, harness.NewFakeStore(shardedFruits...).List()); err != nil {
+		t.Fatal(err)
+	}
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	coordinator, close := startShardFiles(t, path)
+	added, err := coordinator.AddOrder("durian")
+	if err != nil {
+		close()
+		t.Fatal(err)
+	}
+	if _, err := coordinator.Client.DeleteOrder(ctx, &pb.OrderID{Id: "3"}); err != nil {
+		close()
+		t.Fatal(err)
+	}
+	written, err := coordinator.Client.GetOrderUnary(ctx, &pb.Request{Query: "a"})
+	if err != nil {
+		close()
+		t.Fatal(err)
+	}
+	close()
+
+	coordinator, close = startShardFiles(t, path)
+	defer close()
+	got, err := coordinator.Client.GetOrderByID(ctx, &pb.OrderID{Id: added.GetId()})
+	if err != nil {
+		t.Fatalf("order added before the restart: %v", err)
+	}
+	if got.GetName() != "durian" {
+		t.Errorf("got %q after the restart, want durian", got.GetName())
+	}
+	_, err = coordinator.Client.GetOrderByID(ctx, &pb.OrderID{Id: "3"})
+	harnesstest.ExpectCode(t, err, codes.NotFound)
+	res, err := coordinator.Client.GetOrderUnary(ctx, &pb.Request{Query: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(res.GetResults(), written.GetResults()) || !slices.Contains(res.GetResults(), "durian") {
+		t.Errorf("got %v after the restart, want %v", res.GetResults(), written.GetResults())
+	}
+
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Error("the shards rewrote the catalog file")
+	}
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"dist-grpc/pkg/auth"
+	"dist-grpc/pkg/catalog"
+	pb "dist-grpc/pkg/proto"
+)
+
+func validateOrder(order *pb.Order) error {
+	if order.GetName() == "" {
+		return status.Error(codes.InvalidArgument, "order name is required")
+	}
+	if order.GetQuantity() < 0 {
+		return status.Error(codes.InvalidArgument, "order quantity must not be negative")
+	}
+	if order.GetPrice() < 0 {
+		return status.Error(codes.InvalidArgument, "order price must not be negative")
+	}
+	return nil
+}
+
+func catalogError(err error) error {
+	switch {
+	case errors.Is(err, catalog.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, catalog.ErrExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, catalog.ErrNotLeader):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, catalog.ErrNotReplicated):
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func newOrderID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func (s *Server) AddOrder(ctx context.Context, req *pb.Order) (*pb.Order, error) {
+	if ctx.Err() != nil {
+		return nil, contextError(ctx, nil)
+	}
+	if err := validateOrder(req); err != nil {
+		return nil, err
+	}
+	order := catalog.FromProto(req)
+	if order.ID == "" {
+		order.ID = newOrderID()
+	}
+	if order.Status == "" {
+		order.Status = catalog.StatusPending
+	}
+	if order.Created.IsZero() {
+		order.Created = time.Now().UTC()
+	}
+	if err := s.store.Add(order); err != nil {
+		return nil, catalogError(err)
+	}
+	log.Info("Added order", "id", order.ID, "identity", auth.Caller(ctx))
+	return catalog.ToProto(order), nil
+}
+
+func (s *Server) UpdateOrder(ctx context.Context, req *pb.Order) (*pb.Order, error) {
+	if ctx.Err() != nil {
+		return nil, contextError(ctx, nil)
+	}
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "order id is required")
+	}
+	if err := validateOrder(req); err != nil {
+		return nil, err
+	}
+	old, err := s.store.Get(req.GetId())
+	if err != nil {
+		return nil, catalogError(err)
+	}
+	order := catalog.FromProto(req)
+	if order.Status == "" {
+		order.Status = old.Status
+	}
+	order.Created = old.Created
+	if err := s.store.Update(order); err != nil {
+		return nil, catalogError(err)
+	}
+	log.Info("Updated order", "id", order.ID, "identity", auth.Caller(ctx))
+	return catalog.ToProto(order), nil
+}
+
+func (s *Server) DeleteOrder(ctx context.Context, req *pb.OrderID) (*pb.Order, error) {
+	if ctx.Err() != nil {
+		return nil, contextError(ctx, nil)
+	}
+	order, err := s.store.Get(req.GetId())
+	if err != nil {
+		return nil, catalogError(err)
+	}
+	if err := s.store.Delete(req.GetId()); err != nil {
+		return nil, catalogError(err)
+	}
+	log.Info("Deleted order", "id", order.ID, "identity", auth.Caller(ctx))
+	return catalog.ToProto(order), nil
+}
+
+func (s *Server) GetOrderByID(ctx context.Context, req *pb.OrderID) (*pb.Order, error) {
+	if ctx.Err() != nil {
+		return nil, contextError(ctx, nil)
+	}
+	if err := s.barrier(ctx, req.GetConsistency()); err != nil {
+		return nil, err
+	}
+	order, err := s.store.Get(req.GetId())
+	if err != nil {
+		return nil, catalogError(err)
+	}
+	return catalog.ToProto(order), nil
+}
@@ -0,0 +1,224 @@
+package server
+
+import (
+	"context"
+	"io"
+
+	"github.com/charmbracelet/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"dist-grpc/pkg/catalog"
+	"dist-grpc/pkg/matcher"
+	"dist-grpc/pkg/pagination"
+	pb "dist-grpc/pkg/proto"
+	"dist-grpc/pkg/utils"
+	"dist-grpc/pkg/watch"
+)
+
+// Server implements the OrderManagement service over a catalog store.
+type Server struct {
+	pb.UnimplementedOrderManagementServer
+	store   catalog.Store
+	matcher *matcher.Matcher
+	hub     *watch.Hub
+	config  Config
+	// search answers the queries of all four patterns; a coordinator
+	// gathers them from its shards instead of the local matcher.
+	search func(ctx context.Context, req *pb.Request) (matches, error)
+}
+
+type Config struct {
+	// BiDiWorkers is how many requests of one bidirectional stream are
+	// matched at the same time; 0 or 1 answers them one at a time.
+	BiDiWorkers int
+	// BiDiUnordered lets a response overtake those of earlier requests on
+	// the same stream instead of waiting for them.
+	BiDiUnordered bool
+}
+
+func New(store catalog.Store, m *matcher.Matcher, hub *watch.Hub, config Config) *Server {
+	s := &Server{store: store, matcher: m, hub: hub, config: config}
+	s.search = s.match
+	return s
+}
+
+// contextError reports why the caller went away, as codes.Canceled or
+// codes.DeadlineExceeded, in place of err once the context is done.
+func contextError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	return err
+}
+
+var matchModes = map[pb.MatchMode]matcher.Mode{
+	pb.MatchMode_MATCH_MODE_SUBSTRING:        matcher.Substring,
+	pb.MatchMode_MATCH_MODE_EXACT:            matcher.Exact,
+	pb.MatchMode_MATCH_MODE_CASE_INSENSITIVE: matcher.CaseInsensitive,
+	pb.MatchMode_MATCH_MODE_PREFIX:           matcher.Prefix,
+	pb.MatchMode_MATCH_MODE_TOKEN:            matcher.Token,
+	pb.MatchMode_MATCH_MODE_FUZZY:            matcher.Fuzzy,
+}
+
+// matches are the results of a query. Results gathered from shards leave
+// out those of the shards listed in missing.
+type matches struct {
+	results []matcher.Result
+	missing []string
+}
+
+// markPartial flags a response whose matches miss some shards.
+func (m matches) markPartial(resp *pb.Response) *pb.Response {
+	if len(m.missing) > 0 {
+		resp.Partial = true
+		resp.MissingShards = m.missing
+	}
+	return resp
+}
+
+// barrier makes a linearizable read wait for the catalog to be current.
+// Stores that are not replicated are always current.
+func (s *Server) barrier(ctx context.Context, consistency pb.ReadConsistency) error {
+	b, ok := s.store.(catalog.Barrier)
+	if !ok || consistency != pb.ReadConsistency_READ_CONSISTENCY_LINEARIZABLE {
+		return nil
+	}
+	if err := b.Barrier(ctx); err != nil {
+		return contextError(ctx, status.Errorf(codes.Unavailable, "linearizable read failed: %v", err))
+	}
+	return nil
+}
+
+func (s *Server) match(ctx context.Context, req *pb.Request) (matches, error) {
+	if err := s.barrier(ctx, req.GetConsistency()); err != nil {
+		return matches{}, err
+	}
+	res, err := s.matcher.MatchContext(ctx, req.GetQuery(), matchModes[req.GetMode()])
+	if err != nil {
+		return matches{}, contextError(ctx, err)
+	}
+	return matches{results: res}, nil
+}
+
+func pageKey(queries ...*pb.Request) string {
+	key := ""
+	for _, req := range queries {
+		key += req.GetMode().String() + ":" + req.GetQuery() + "\n"
+	}
+	return key
+}
+
+func paginate[T any](req *pb.Request, key string, res []T) (pagination.Page[T], error) {
+	page, err := pagination.Paginate(res, pagination.Params{
+		PageSize:   int(req.GetPageSize()),
+		MaxResults: int(req.GetMaxResults()),
+		PageToken:  req.GetPageToken(),
+		Key:        key,
+	})
+	if err != nil {
+		return page, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return page, nil
+}
+
+func newResponse(req *pb.Request, page pagination.Page[matcher.Result]) *pb.Response {
+	return &pb.Response{
+		RequestId:     req.GetRequestId(),
+		Results:       matcher.Names(page.Items),
+		Scores:        matcher.Scores(page.Items),
+		Timestamp:     timestamppb.Now(),
+		NextPageToken: page.NextPageToken,
+		TotalCount:    int32(page.TotalCount),
+	}
+}
+
+func (s *Server) GetOrderUnary(ctx context.Context, req *pb.Request) (*pb.Response, error) {
+	log.Debug("Received request", "query", req.GetQuery(), "mode", req.GetMode())
+	if err := validateQuery(req.GetQuery()); err != nil {
+		return nil, err
+	}
+	m, err := s.search(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("Matched orders", "orders", utils.ToString(matcher.Names(m.results)))
+	page, err := paginate(req, pageKey(req), m.results)
+	if err != nil {
+		return nil, err
+	}
+	return m.markPartial(newResponse(req, page)), nil
+}
+
+func (s *Server) GetOrderServerStream(req *pb.Request, stream pb.OrderManagement_GetOrderServerStreamServer) error {
+	ctx := stream.Context()
+	log.Debug("Received request", "query", req.GetQuery(), "mode", req.GetMode())
+	if err := validateQuery(req.GetQuery()); err != nil {
+		return err
+	}
+	m, err := s.search(ctx, req)
+	if err != nil {
+		return err
+	}
+	log.Debug("Matched orders", "orders", utils.ToString(matcher.Names(m.results)))
+	page, err := paginate(req, pageKey(req), m.results)
+	if err != nil {
+		return err
+	}
+	for _, v := range page.Items {
+		// Send only fails once the transport is gone, so stop as soon as
+		// the caller cancels instead of queueing the rest of the page.
+		if ctx.Err() != nil {
+			return contextError(ctx, nil)
+		}
+		resp := pb.Response{
+			RequestId:     req.GetRequestId(),
+			Results:       []string{v.Order.Name},
+			Scores:        []float64{v.Score},
+			Timestamp:     timestamppb.Now(),
+			NextPageToken: page.NextPageToken,
+			TotalCount:    int32(page.TotalCount),
+		}
+		if err := stream.Send(m.markPartial(&resp)); err != nil {
+			return contextError(ctx, err)
+		}
+	}
+	return nil
+}
+
+// GetOrderClientStream skips invalid queries rather than failing the whole
+// stream and reports them in the status of the response, one field
+// violation per rejected request.
+func (s *Server) GetOrderClientStream(stream pb.OrderManagement_GetOrderClientStreamServer) error {
+	ctx := stream.Context()
+	var queries []queryMatches
+	first := &pb.Request{}
+	for i := 0; ; i++ {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			log.Debug("Received EOF, closing stream")
+			break
+		}
+		if err != nil {
+			return contextError(ctx, err)
+		}
+		if i == 0 {
+			first = req
+		}
+		log.Debug("Received request", "query", req.GetQuery(), "mode", req.GetMode())
+		q := queryMatches{index: i, req: req, reason: checkQuery(req.GetQuery())}
+		if q.reason == "" {
+			if q.matches, err = s.search(ctx, req); err != nil {
+				return err
+			}
+			log.Debug("Matched orders", "orders", utils.ToString(matcher.Names(q.results)))
+		}
+		queries = append(queries, q)
+	}
+	resp, err := aggregate(first, queries)
+	if err != nil {
+		return err
+	}
+	return contextError(ctx, stream.SendAndClose(resp))
+}
@@ -0,0 +1,271 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+
+	"dist-grpc/internal/harnesstest"
+	"dist-grpc/pkg/harness"
+	"dist-grpc/pkg/matcher"
+	"dist-grpc/pkg/orderclient"
+	pb "dist-grpc/pkg/proto"
+	"dist-grpc/pkg/server"
+)
+
+// startFake serves the fake catalog and returns a client of it, whose calls
+// are bounded by harness.HandledTimeout.
+func startFake(t *testing.T, store *harness.FakeStore, opts orderclient.Options) (*harness.Harness, *orderclient.Client) {
+	t.Helper()
+	h, err := harness.Start(store, matcher.New(store), server.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(h.Close)
+	opts.CallTimeout = harness.HandledTimeout
+	return h, orderclient.New(h.Conn, opts)
+}
+
+func results(responses []orderclient.Response) []string {
+	var names []string
+	for _, res := range responses {
+		names = append(names, res.GetResults()...)
+	}
+	return names
+}
+
+func queryChannel(queries ...string) <-chan string {
+	ch := make(chan string, len(queries))
+	for _, query := range queries {
+		ch <- query
+	}
+	close(ch)
+	return ch
+}
+
+func TestUnary(t *testing.T) {
+	_, client := startFake(t, harness.NewFakeStore(harness.Fruits...), orderclient.Options{})
+
+	pages, err := client.Unary(context.Background(), "apple")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pages) != 1 || pages[0].GetTotalCount() != 4 || pages[0].GetRequestId() == "" {
+		t.Fatalf("got %v, want a single page of 4 results with a request id", pages)
+	}
+	if pages[0].GetScores()[0] != 1 {
+		t.Fatalf("exact name scored %v, want 1", pages[0].GetScores()[0])
+	}
+	harnesstest.ExpectResults(t, results(pages), harness.AppleResults...)
+}
+
+func TestUnaryPages(t *testing.T) {
+	h, client := startFake(t, harness.NewFakeStore(harness.Fruits...), orderclient.Options{PageSize: 3})
+
+	pages, err := client.Unary(context.Background(), "apple")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pages) != 2 || pages[0].GetNextPageToken() == "" || pages[1].GetNextPageToken() != "" {
+		t.Fatalf("got %d pages, want 2 linked by a page token", len(pages))
+	}
+	harnesstest.ExpectResults(t, results(pages), harness.AppleResults...)
+	_, err = h.Client.GetOrderUnary(context.Background(), &pb.Request{Query: "apple", PageToken: "bogus"})
+	harnesstest.ExpectCode(t, err, codes.InvalidArgument)
+}
+
+func TestServerStream(t *testing.T) {
+	_, client := startFake(t, harness.NewFakeStore(harness.Fruits...), orderclient.Options{PageSize: 3})
+
+	var responses []orderclient.Response
+	if err := client.ServerStream(context.Background(), "apple", orderclient.Collect(&responses)); err != nil {
+		t.Fatal(err)
+	}
+	if len(responses) != len(harness.AppleResults) {
+		t.Fatalf("got %d responses, want one per result", len(responses))
+	}
+	for i, res := range responses {
+		if len(res.GetResults()) != 1 || (i < 3) != (res.GetNextPageToken() != "") {
+			t.Fatalf("response %d is %v", i, res)
+		}
+	}
+	harnesstest.ExpectResults(t, results(responses), harness.AppleResults...)
+}
+
+// TestServerStreamEmpty checks that a stream without matches ends with a
+// clean EOF.
+func TestServerStreamEmpty(t *testing.T) {
+	_, client := startFake(t, harness.NewFakeStore(harness.Fruits...), orderclient.Options{})
+
+	var responses []orderclient.Response
+	if err := client.ServerStream(context.Background(), "durian", orderclient.Collect(&responses)); err != nil {
+		t.Fatal(err)
+	}
+	if len(responses) != 0 {
+		t.Fatalf("got %d responses, want none", len(responses))
+	}
+}
+
+func TestServerStreamCancel(t *testing.T) {
+	_, client := startFake(t, harness.NewFakeStore(harness.Fruits...), orderclient.Options{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	received := 0
+	err := client.ServerStream(ctx, "apple", func(orderclient.Response) {
+		received++
+		cancel()
+	})
+	harnesstest.ExpectCode(t, err, codes.Canceled)
+	if received != 1 {
+		t.Fatalf("handled %d responses after cancelling, want 1", received)
+	}
+}
+
+func TestClientStream(t *testing.T) {
+	_, client := startFake(t, harness.NewFakeStore(harness.Fruits...), orderclient.Options{})
+
+	res, err := client.ClientStream(context.Background(), queryChannel("apple", "banana", "apple pie"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// apple pie keeps its best score, 1 from its own query.
+	harnesstest.ExpectResults(t, res.GetResults(), "apple", "apple pie", "banana", "pineapple", "green apple")
+}
+
+func TestClientStreamEmpty(t *testing.T) {
+	_, client := startFake(t, harness.NewFakeStore(harness.Fruits...), orderclient.Options{})
+
+	res, err := client.ClientStream(context.Background(), queryChannel())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.GetResults()) != 0 || res.GetStatus() != nil {
+		t.Fatalf("got %v, want an empty response", res)
+	}
+}
+
+func TestBiDi(t *testing.T) {
+	_, client := startFake(t, harness.NewFakeStore(harness.Fruits...), orderclient.Options{Window: 1})
+
+	queries := []string{"apple", "cherry", "durian", "banana"}
+	var responses []orderclient.Response
+	if err := client.BiDi(context.Background(), queryChannel(queries...), orderclient.Collect(&responses)); err != nil {
+		t.Fatal(err)
+	}
+	if len(responses) != len(queries) {
+		t.Fatalf("got %d responses, want %d", len(responses), len(queries))
+	}
+	want := [][]string{harness.AppleResults, {"cherry"}, nil, {"banana"}}
+	for i, res := range responses {
+		if !slices.Equal(res.GetResults(), want[i]) {
+			t.Fatalf("response %d has results %v, want %v", i, res.GetResults(), want[i])
+		}
+		if i > 0 && res.GetRequestId() <= responses[i-1].GetRequestId() {
+			t.Fatalf("response %d has request id %s after %s", i, res.GetRequestId(), responses[i-1].GetRequestId())
+		}
+	}
+}
+
+func TestBiDiEmpty(t *testing.T) {
+	_, client := startFake(t, harness.NewFakeStore(harness.Fruits...), orderclient.Options{})
+
+	var responses []orderclient.Response
+	if err := client.BiDi(context.Background(), queryChannel(), orderclient.Collect(&responses)); err != nil {
+		t.Fatal(err)
+	}
+	if len(responses) != 0 {
+		t.Fatalf("got %d responses, want none", len(responses))
+	}
+}
+
+// TestBiDiServerGone stops the server while the stream waits for the next
+// query, which then fails instead of hanging.
+func TestBiDiServerGone(t *testing.T) {
+	h, client := startFake(t, harness.NewFakeStore(harness.Fruits...), orderclient.Options{})
+
+	queries := make(chan string)
+	answered := make(chan struct{}, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.BiDi(context.Background(), queries, func(orderclient.Response) {
+			answered <- struct{}{}
+		})
+	}()
+	queries <- "apple"
+	<-answered
+	h.Server.Stop()
+
+	timeout := time.After(harness.HandledTimeout)
+	for {
+		select {
+		case queries <- "cherry":
+			continue
+		case err := <-done:
+			harnesstest.ExpectCode(t, err, codes.Unavailable)
+			return
+		case <-timeout:
+			t.Fatal("stream did not fail after the server stopped")
+		}
+	}
+}
+
+// TestSlowCatalog gives up on a scan of a slow catalog through the client's
+// own call timeout.
+func TestSlowCatalog(t *testing.T) {
+	store := harness.NewFakeStore(harness.Fruits...)
+	store.ListDelay = 500 * time.Millisecond
+	h, err := harness.Start(store, matcher.NewLinear(store), server.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(h.Close)
+
+	client := orderclient.New(h.Conn, orderclient.Options{CallTimeout: 50 * time.Millisecond})
+	_, err = client.Unary(context.Background(), "apple")
+	harnesstest.ExpectCode(t, err, codes.DeadlineExceeded)
+	if store.Lists() != 1 {
+		t.Fatalf("catalog was read %d times, want once", store.Lists())
+	}
+}
+
+func TestCRUD(t *testing.T) {
+	_, client := startFake(t, harness.NewFakeStore(harness.Fruits...), orderclient.Options{})
+
+	ctx := context.Background()
+	added, err := client.RPC().AddOrder(ctx, &pb.Order{Name: "durian", Quantity: 2, Price: 9.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added.GetId() == "" || added.GetStatus() != pb.OrderStatus_ORDER_STATUS_PENDING {
+		t.Fatalf("added order %v has no id or is not pending", added)
+	}
+	if _, err := client.RPC().UpdateOrder(ctx, &pb.Order{Id: added.GetId(), Name: "ripe durian"}); err != nil {
+		t.Fatal(err)
+	}
+	pages, err := client.Unary(ctx, "durian")
+	if err != nil {
+		t.Fatal(err)
+	}
+	harnesstest.ExpectResults(t, results(pages), "ripe durian")
+	if _, err := client.RPC().DeleteOrder(ctx, &pb.OrderID{Id: added.GetId()}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.RPC().GetOrderByID(ctx, &pb.OrderID{Id: added.GetId()})
+	harnesstest.ExpectCode(t, err, codes.NotFound)
+	_, err = client.RPC().AddOrder(ctx, &pb.Order{Id: "1", Name: "apple"})
+	harnesstest.ExpectCode(t, err, codes.AlreadyExists)
+}
+
+func TestCRUDStoreFailure(t *testing.T) {
+	store := harness.NewFakeStore(harness.Fruits...)
+	store.WriteErr = errors.New("disk full")
+	_, client := startFake(t, store, orderclient.Options{})
+
+	_, err := client.RPC().AddOrder(context.Background(), &pb.Order{Name: "durian"})
+	harnesstest.ExpectCode(t, err, codes.Internal)
+}
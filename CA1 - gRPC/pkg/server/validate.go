@@ -0,0 +1,58 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	maxQueryLength = 200
+	// allowedPunctuation is what order names use besides letters, digits
+	// and spaces.
+	allowedPunctuation = "-'.,&/()#+%"
+)
+
+// checkQuery returns why the query is rejected, or "" if it is valid.
+func checkQuery(query string) string {
+	if strings.TrimSpace(query) == "" {
+		return "query must not be empty"
+	}
+	if n := utf8.RuneCountInString(query); n > maxQueryLength {
+		return fmt.Sprintf("query is %d characters long, at most %d are allowed", n, maxQueryLength)
+	}
+	for _, r := range query {
+		if r == utf8.RuneError {
+			return "query is not valid UTF-8"
+		}
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != ' ' && !strings.ContainsRune(allowedPunctuation, r) {
+			return fmt.Sprintf("query contains the disallowed character %q", r)
+		}
+	}
+	return ""
+}
+
+func invalidQuery(violations ...*errdetails.BadRequest_FieldViolation) *status.Status {
+	msg := violations[0].GetDescription()
+	if len(violations) > 1 {
+		msg = fmt.Sprintf("%d queries are invalid", len(violations))
+	}
+	st := status.New(codes.InvalidArgument, msg)
+	if detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); err == nil {
+		return detailed
+	}
+	return st
+}
+
+// validateQuery checks the query of a request that makes up the whole RPC.
+func validateQuery(query string) error {
+	if reason := checkQuery(query); reason != "" {
+		return invalidQuery(&errdetails.BadRequest_FieldViolation{Field: "query", Description: reason}).Err()
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package server_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"dist-grpc/pkg/harness"
+	pb "dist-grpc/pkg/proto"
+)
+
+var invalidQueries = []string{"", "   ", strings.Repeat("a", 201), "apple; drop", "kiwi\x00"}
+
+func TestValidateUnary(t *testing.T) {
+	h := startDefault(t)
+
+	for _, query := range invalidQueries {
+		_, err := h.Client.GetOrderUnary(context.Background(), &pb.Request{Query: query})
+		if got := status.Code(err); got != codes.InvalidArgument {
+			t.Fatalf("query %q got code %s, want %s: %v", query, got, codes.InvalidArgument, err)
+		}
+	}
+	if _, err := h.Client.GetOrderUnary(context.Background(), &pb.Request{Query: "Ben & Jerry's (1/2)"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestValidateBiDi checks that invalid queries are answered with a status in
+// their response while the stream goes on answering the valid ones.
+func TestValidateBiDi(t *testing.T) {
+	h := startDefault(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), harness.HandledTimeout)
+	defer cancel()
+	stream, err := h.Client.GetOrderBiDiStream(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	queries := append([]string{"apple"}, invalidQueries...)
+	queries = append(queries, "kiwi")
+	for i, query := range queries {
+		if err := stream.Send(&pb.Request{Query: query, RequestId: fmt.Sprint(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+	for i := range queries {
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		valid := i == 0 || i == len(queries)-1
+		code := codes.Code(resp.GetStatus().GetCode())
+		if valid && (code != codes.OK || len(resp.GetResults()) == 0) {
+			t.Fatalf("query %q got %s with %v", queries[i], code, resp.GetResults())
+		}
+		if !valid && code != codes.InvalidArgument {
+			t.Fatalf("query %q got %s, want %s", queries[i], code, codes.InvalidArgument)
+		}
+	}
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Fatalf("stream ended with %v, want EOF", err)
+	}
+}
+
+func TestValidateClientStream(t *testing.T) {
+	h := startDefault(t)
+
+	stream, err := h.Client.GetOrderClientStream(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, query := range []string{"apple", "kiwi<", "grape"} {
+		if err := stream.Send(&pb.Request{Query: query}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.GetResults()) != 4 {
+		t.Fatalf("got results %v, want the matches of apple and grape", resp.GetResults())
+	}
+	st := status.FromProto(resp.GetStatus())
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("got status %s, want %s", st.Code(), codes.InvalidArgument)
+	}
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok && len(badRequest.GetFieldViolations()) == 1 &&
+			badRequest.GetFieldViolations()[0].GetField() == "requests[1].query" {
+			return
+		}
+	}
+	t.Fatalf("status %v does not point at requests[1].query", st.Details())
+}
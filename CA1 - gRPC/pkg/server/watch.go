@@ -0,0 +1,106 @@
+package server
+
+import (
+	"errors"
+
+	"github.com/charmbracelet/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"dist-grpc/pkg/auth"
+	"dist-grpc/pkg/catalog"
+	"dist-grpc/pkg/matcher"
+	pb "dist-grpc/pkg/proto"
+	"dist-grpc/pkg/watch"
+)
+
+func watchError(err error) error {
+	switch {
+	case errors.Is(err, watch.ErrOverflow):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, watch.ErrClosed):
+		return status.Error(codes.Unavailable, "server is shutting down")
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func newEvent(kind pb.EventType, order catalog.Order, score float64) *pb.OrderEvent {
+	return &pb.OrderEvent{
+		Type:      kind,
+		Order:     catalog.ToProto(order),
+		Score:     score,
+		Timestamp: timestamppb.Now(),
+	}
+}
+
+// watchEvent turns a catalog change into the event it means for a query
+// whose matches are tracked in matched, or nil when the query is unaffected.
+func watchEvent(change catalog.Change, score func(string) (float64, bool), matched map[string]bool) *pb.OrderEvent {
+	if change.Kind == catalog.Removed {
+		if !matched[change.Old.ID] {
+			return nil
+		}
+		delete(matched, change.Old.ID)
+		return newEvent(pb.EventType_EVENT_TYPE_REMOVED, change.Old, 0)
+	}
+	id := change.New.ID
+	s, matches := score(change.New.Name)
+	switch {
+	case matches && !matched[id]:
+		matched[id] = true
+		return newEvent(pb.EventType_EVENT_TYPE_ADDED, change.New, s)
+	case matches && change.Kind == catalog.Updated:
+		return newEvent(pb.EventType_EVENT_TYPE_UPDATED, change.New, s)
+	case !matches && matched[id]:
+		delete(matched, id)
+		return newEvent(pb.EventType_EVENT_TYPE_REMOVED, change.New, 0)
+	}
+	return nil
+}
+
+func (s *Server) WatchOrders(req *pb.Request, stream pb.OrderManagement_WatchOrdersServer) error {
+	ctx := stream.Context()
+	log.Debug("Watching", "query", req.GetQuery(), "mode", req.GetMode(), "identity", auth.Caller(ctx))
+	if err := validateQuery(req.GetQuery()); err != nil {
+		return err
+	}
+	// Subscribing before matching means no change is missed between the two;
+	// changes already reflected in the initial matches are skipped below.
+	sub := s.hub.Subscribe()
+	defer sub.Close()
+
+	m, err := s.match(ctx, req)
+	if err != nil {
+		return err
+	}
+	matched := make(map[string]bool, len(m.results))
+	for _, r := range m.results {
+		matched[r.Order.ID] = true
+		event := newEvent(pb.EventType_EVENT_TYPE_ADDED, r.Order, r.Score)
+		event.Initial = true
+		if err := stream.Send(event); err != nil {
+			return contextError(ctx, err)
+		}
+	}
+
+	score := matcher.Scorer(req.GetQuery(), matchModes[req.GetMode()])
+	for {
+		select {
+		case <-ctx.Done():
+			return contextError(ctx, nil)
+		case change, ok := <-sub.Changes():
+			if !ok {
+				return watchError(sub.Err())
+			}
+			event := watchEvent(change, score, matched)
+			if event == nil {
+				continue
+			}
+			if err := stream.Send(event); err != nil {
+				return contextError(ctx, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package server_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+
+	"dist-grpc/internal/harnesstest"
+	"dist-grpc/pkg/catalog"
+	"dist-grpc/pkg/harness"
+	"dist-grpc/pkg/matcher"
+	pb "dist-grpc/pkg/proto"
+	"dist-grpc/pkg/server"
+)
+
+const watchTimeout = 5 * time.Second
+
+// startDefault serves the built-in catalog.
+func startDefault(t *testing.T) *harness.Harness {
+	t.Helper()
+	store := catalog.NewMemoryStore(catalog.DefaultOrders)
+	h, err := harness.Start(store, matcher.New(store), server.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(h.Close)
+	return h
+}
+
+type wantEvent struct {
+	kind    pb.EventType
+	name    string
+	initial bool
+}
+
+func receiveEvents(stream pb.OrderManagement_WatchOrdersClient, want ...wantEvent) error {
+	for _, w := range want {
+		event, err := stream.Recv()
+		if err != nil {
+			return fmt.Errorf("waiting for %s %q: %w", w.kind, w.name, err)
+		}
+		got := wantEvent{event.GetType(), event.GetOrder().GetName(), event.GetInitial()}
+		if got != w {
+			return fmt.Errorf("got event %+v, want %+v", got, w)
+		}
+	}
+	return nil
+}
+
+func expectEvents(t *testing.T, stream pb.OrderManagement_WatchOrdersClient, want ...wantEvent) {
+	t.Helper()
+	if err := receiveEvents(stream, want...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func watchQuery(t *testing.T, h *harness.Harness, query string) pb.OrderManagement_WatchOrdersClient {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), watchTimeout)
+	t.Cleanup(cancel)
+	stream, err := h.Client.WatchOrders(ctx, &pb.Request{Query: query})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return stream
+}
+
+func TestWatchEvents(t *testing.T) {
+	h := startDefault(t)
+	stream := watchQuery(t, h, "apple")
+	expectEvents(t, stream,
+		wantEvent{pb.EventType_EVENT_TYPE_ADDED, "apple", true},
+		wantEvent{pb.EventType_EVENT_TYPE_ADDED, "red apple", true},
+		wantEvent{pb.EventType_EVENT_TYPE_ADDED, "green apple", true},
+	)
+
+	steps := []func() error{
+		func() error { return h.Store.Add(catalog.Order{ID: "11", Name: "kiwi juice"}) },
+		func() error { return h.Store.Add(catalog.Order{ID: "12", Name: "apple pie"}) },
+		func() error { return h.Store.Update(catalog.Order{ID: "12", Name: "apple tart"}) },
+		func() error { return h.Store.Update(catalog.Order{ID: "12", Name: "banana split"}) },
+		func() error { return h.Store.Update(catalog.Order{ID: "11", Name: "kiwi and apple"}) },
+		func() error { return h.Store.Delete("5") },
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Changes that do not touch the matches, like adding "kiwi juice", send
+	// nothing, so the next event is the one for "apple pie".
+	expectEvents(t, stream,
+		wantEvent{pb.EventType_EVENT_TYPE_ADDED, "apple pie", false},
+		wantEvent{pb.EventType_EVENT_TYPE_UPDATED, "apple tart", false},
+		wantEvent{pb.EventType_EVENT_TYPE_REMOVED, "banana split", false},
+		wantEvent{pb.EventType_EVENT_TYPE_ADDED, "kiwi and apple", false},
+		wantEvent{pb.EventType_EVENT_TYPE_REMOVED, "red apple", false},
+	)
+}
+
+func TestWatchFanOut(t *testing.T) {
+	const watchers = 50
+	h := startDefault(t)
+
+	var streams []pb.OrderManagement_WatchOrdersClient
+	for i := 0; i < watchers; i++ {
+		stream := watchQuery(t, h, "grape")
+		expectEvents(t, stream, wantEvent{pb.EventType_EVENT_TYPE_ADDED, "grape", true})
+		streams = append(streams, stream)
+	}
+	if err := h.Store.Add(catalog.Order{ID: "11", Name: "grape juice"}); err != nil {
+		t.Fatal(err)
+	}
+	errs := make(chan error, watchers)
+	for _, stream := range streams {
+		go func(stream pb.OrderManagement_WatchOrdersClient) {
+			errs <- receiveEvents(stream, wantEvent{pb.EventType_EVENT_TYPE_ADDED, "grape juice", false})
+		}(stream)
+	}
+	for i := 0; i < watchers; i++ {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestWatchShutdown(t *testing.T) {
+	h := startDefault(t)
+	stream := watchQuery(t, h, "kiwi")
+	expectEvents(t, stream, wantEvent{pb.EventType_EVENT_TYPE_ADDED, "kiwi", true})
+
+	h.Hub.Close()
+	_, err := stream.Recv()
+	harnesstest.ExpectCode(t, err, codes.Unavailable)
+	harnesstest.ExpectHandled(t, h, codes.Unavailable)
+}
@@ -0,0 +1,140 @@
+package tlsutil
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type files struct {
+	cert, key string
+}
+
+func writePair(t *testing.T, kp *KeyPair, name string) files {
+	t.Helper()
+	f := files{filepath.Join(t.TempDir(), name+".pem"), filepath.Join(t.TempDir(), name+"-key.pem")}
+	if err := kp.WriteFiles(f.cert, f.key); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// handshake runs both sides of a TLS handshake over a loopback connection
+// and returns the error each side saw.
+func handshake(t *testing.T, server, client files, serverCA, clientCA string, requireClientCert bool) (serverErr, clientErr error) {
+	t.Helper()
+	serverCreds, err := ServerCredentials(server.cert, server.key, clientCA, requireClientCert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCreds, err := ClientCredentials(serverCA, client.cert, client.key, "localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		serverConn, err := lis.Accept()
+		if err != nil {
+			done <- err
+			return
+		}
+		defer serverConn.Close()
+		_ = serverConn.SetDeadline(time.Now().Add(5 * time.Second))
+		conn, _, err := serverCreds.ServerHandshake(serverConn)
+		if err == nil {
+			// The server only checks the client's certificate after the client
+			// finished its side of the handshake, so read to learn the outcome.
+			_, err = conn.Read(make([]byte, 1))
+		}
+		done <- err
+	}()
+	clientConn, err := net.Dial("tcp", lis.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clientConn.Close()
+	conn, _, clientErr := clientCreds.ClientHandshake(ctx, "localhost", clientConn)
+	if clientErr == nil {
+		_, _ = conn.Write([]byte{0})
+	}
+	return <-done, clientErr
+}
+
+func TestMutualTLS(t *testing.T) {
+	ca, err := GenerateCA("test CA", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCA, err := GenerateCA("other CA", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverKP, err := GenerateLeaf(ca, "server", []string{"localhost", "127.0.0.1"}, true, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientKP, err := GenerateLeaf(ca, "client", nil, false, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	strangerKP, err := GenerateLeaf(otherCA, "stranger", nil, false, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caFile := writePair(t, ca, "ca").cert
+	otherCAFile := writePair(t, otherCA, "other-ca").cert
+	server := writePair(t, serverKP, "server")
+	client := writePair(t, clientKP, "client")
+	stranger := writePair(t, strangerKP, "stranger")
+
+	tests := []struct {
+		name              string
+		client            files
+		serverCA          string
+		requireClientCert bool
+		wantServerErr     bool
+		wantClientErr     bool
+	}{
+		{"client certificate", client, caFile, true, false, false},
+		{"missing client certificate", files{}, caFile, true, true, false},
+		{"optional client certificate", files{}, caFile, false, false, false},
+		{"client certificate from another CA", stranger, caFile, true, true, false},
+		{"server from another CA", client, otherCAFile, true, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serverErr, clientErr := handshake(t, server, tt.client, tt.serverCA, caFile, tt.requireClientCert)
+			if (serverErr != nil) != tt.wantServerErr {
+				t.Errorf("server handshake: %v, want error: %v", serverErr, tt.wantServerErr)
+			}
+			if (clientErr != nil) != tt.wantClientErr {
+				t.Errorf("client handshake: %v, want error: %v", clientErr, tt.wantClientErr)
+			}
+		})
+	}
+}
+
+func TestRequireClientCertNeedsCA(t *testing.T) {
+	ca, err := GenerateCA("test CA", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kp, err := GenerateLeaf(ca, "server", []string{"localhost"}, true, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := writePair(t, kp, "server")
+	if _, err := ServerCredentials(server.cert, server.key, "", true); err == nil {
+		t.Error("required client certificates without a CA")
+	}
+}
@@ -0,0 +1,111 @@
+// Package watch fans the changes of a catalog store out to many concurrent
+// watchers, each with a bounded buffer so that a slow watcher can never hold
+// up the store or the other watchers.
+package watch
+
+import (
+	"errors"
+	"sync"
+
+	"dist-grpc/pkg/catalog"
+)
+
+var (
+	ErrOverflow = errors.New("watcher fell behind the catalog changes")
+	ErrClosed   = errors.New("watch hub closed")
+)
+
+const DefaultBuffer = 64
+
+type Hub struct {
+	mu          sync.Mutex
+	subs        map[*Subscription]struct{}
+	buffer      int
+	closed      bool
+	unsubscribe func()
+}
+
+type Subscription struct {
+	hub     *Hub
+	changes chan catalog.Change
+	err     error
+}
+
+// NewHub follows the store's changes. Every subscription buffers up to
+// buffer changes and is dropped with ErrOverflow once its buffer is full.
+func NewHub(store catalog.Store, buffer int) *Hub {
+	h := &Hub{subs: make(map[*Subscription]struct{}), buffer: max(buffer, 1)}
+	h.unsubscribe = store.Subscribe(h.publish)
+	return h
+}
+
+// Subscribe returns a subscription receiving every change from now on.
+func (h *Hub) Subscribe() *Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	sub := &Subscription{hub: h, changes: make(chan catalog.Change, h.buffer)}
+	if h.closed {
+		sub.err = ErrClosed
+		close(sub.changes)
+		return sub
+	}
+	h.subs[sub] = struct{}{}
+	return sub
+}
+
+func (h *Hub) Len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs)
+}
+
+// Close stops following the store and ends every subscription with
+// ErrClosed.
+func (h *Hub) Close() {
+	h.unsubscribe()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for sub := range h.subs {
+		h.drop(sub, ErrClosed)
+	}
+}
+
+// publish runs under the store's write lock, so it never blocks.
+func (h *Hub) publish(change catalog.Change) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subs {
+		select {
+		case sub.changes <- change:
+		default:
+			h.drop(sub, ErrOverflow)
+		}
+	}
+}
+
+func (h *Hub) drop(sub *Subscription, err error) {
+	if _, ok := h.subs[sub]; !ok {
+		return
+	}
+	delete(h.subs, sub)
+	sub.err = err
+	close(sub.changes)
+}
+
+// Changes is closed when the subscription ends, after which Err tells why.
+func (s *Subscription) Changes() <-chan catalog.Change {
+	return s.changes
+}
+
+func (s *Subscription) Err() error {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	return s.err
+}
+
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.drop(s, nil)
+}
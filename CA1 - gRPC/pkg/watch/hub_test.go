@@ -0,0 +1,35 @@
+package watch
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"dist-grpc/pkg/catalog"
+)
+
+// TestHubOverflow checks that a subscriber that stops reading is dropped
+// instead of blocking the store.
+func TestHubOverflow(t *testing.T) {
+	store := catalog.NewMemoryStore(nil)
+	hub := NewHub(store, 2)
+	defer hub.Close()
+	slow := hub.Subscribe()
+	fast := hub.Subscribe()
+	defer fast.Close()
+
+	for i := 0; i < 3; i++ {
+		if err := store.Add(catalog.Order{ID: fmt.Sprint(i), Name: "order"}); err != nil {
+			t.Fatal(err)
+		}
+		<-fast.Changes()
+	}
+	for range slow.Changes() {
+	}
+	if !errors.Is(slow.Err(), ErrOverflow) {
+		t.Fatalf("slow subscriber ended with %v, want %v", slow.Err(), ErrOverflow)
+	}
+	if hub.Len() != 1 {
+		t.Fatalf("hub has %d subscribers, want 1", hub.Len())
+	}
+}